@@ -0,0 +1,83 @@
+package nasa
+
+import (
+	"os"
+)
+
+const (
+	// APIKeyEnv is the environment variable the client reads an API key from.
+	APIKeyEnv = "NASA_API_KEY"
+
+	// DemoAPIKey is the heavily rate limited key which works without signing up.
+	DemoAPIKey = "DEMO_KEY"
+)
+
+// WithAPIKeys sets a pool of API keys. Requests use one key until
+// api.nasa.gov reports it has no requests remaining, then rotate to the next.
+func WithAPIKeys(keys ...string) Option {
+	return func(c *Client) {
+		c.apiKeys = nil
+		for _, k := range keys {
+			if k != "" {
+				c.apiKeys = append(c.apiKeys, k)
+			}
+		}
+	}
+}
+
+// WithDemoAPIKeyFallback makes the client use DemoAPIKey when no other key
+// is configured.
+func WithDemoAPIKeyFallback() Option {
+	return func(c *Client) {
+		c.demoKeyFallback = true
+	}
+}
+
+// APIKey returns the key the client will use for its next request, resolved
+// from the key pool, WithAPIKey, the NASA_API_KEY environment variable and
+// finally DemoAPIKey if WithDemoAPIKeyFallback was given. It returns an
+// empty string if there is no key.
+func (c *Client) APIKey() string {
+	if len(c.apiKeys) > 0 {
+		return c.nextPoolKey()
+	}
+
+	if c.apiKey != "" {
+		return c.apiKey
+	}
+
+	if k := os.Getenv(APIKeyEnv); k != "" {
+		return k
+	}
+
+	if c.demoKeyFallback {
+		return DemoAPIKey
+	}
+
+	return ""
+}
+
+// nextPoolKey returns the current pool key, rotating past any which are
+// exhausted. If all are exhausted, the one which resets first is returned.
+func (c *Client) nextPoolKey() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := len(c.apiKeys)
+	best := c.keyIndex
+	for i := 0; i < n; i++ {
+		idx := (c.keyIndex + i) % n
+		r, ok := c.rateLimits[c.apiKeys[idx]]
+		if !ok || !r.Exhausted() {
+			c.keyIndex = idx
+			return c.apiKeys[idx]
+		}
+
+		if r.Reset().Before(c.rateLimits[c.apiKeys[best]].Reset()) {
+			best = idx
+		}
+	}
+
+	c.keyIndex = best
+	return c.apiKeys[best]
+}
@@ -0,0 +1,98 @@
+package nasa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func setenv(key, value string) func() {
+	old, ok := os.LookupEnv(key)
+	if value == "" {
+		os.Unsetenv(key)
+	} else {
+		os.Setenv(key, value)
+	}
+
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	t.Run("option", func(t *testing.T) {
+		defer setenv(APIKeyEnv, "ENV_KEY")()
+
+		c := NewClient(WithAPIKey("OPTION_KEY"))
+		if c.APIKey() != "OPTION_KEY" {
+			t.Errorf("expected: OPTION_KEY, got: %s", c.APIKey())
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		defer setenv(APIKeyEnv, "ENV_KEY")()
+
+		c := NewClient(WithDemoAPIKeyFallback())
+		if c.APIKey() != "ENV_KEY" {
+			t.Errorf("expected: ENV_KEY, got: %s", c.APIKey())
+		}
+	})
+
+	t.Run("demo fallback", func(t *testing.T) {
+		defer setenv(APIKeyEnv, "")()
+
+		c := NewClient(WithDemoAPIKeyFallback())
+		if c.APIKey() != DemoAPIKey {
+			t.Errorf("expected: %s, got: %s", DemoAPIKey, c.APIKey())
+		}
+
+		c = NewClient()
+		if c.APIKey() != "" {
+			t.Errorf("expected no key, got: %s", c.APIKey())
+		}
+
+		_, err := c.APOD(&APODParams{})
+		if err != ErrorNoAPIKey {
+			t.Errorf("wrong error returned: %v", err)
+		}
+	})
+}
+
+func TestAPIKeyPool(t *testing.T) {
+	remaining := map[string]string{"KEY_ONE": "1", "KEY_TWO": "5"}
+	var used []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("api_key")
+		used = append(used, key)
+		w.Header().Set("X-RateLimit-Limit", "5")
+		w.Header().Set("X-RateLimit-Remaining", remaining[key])
+		remaining[key] = "0"
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithAPODURL(ts.URL), WithAPIKeys("KEY_ONE", "KEY_TWO"))
+
+	for i := 0; i < 4; i++ {
+		if _, err := c.APOD(&APODParams{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []string{"KEY_ONE", "KEY_ONE", "KEY_TWO", "KEY_TWO"}
+	for i, key := range expected {
+		if used[i] != key {
+			t.Errorf("request %d: expected: %s, got: %s", i, key, used[i])
+		}
+	}
+
+	// Both keys are exhausted; the first to reset is reused.
+	if key := c.APIKey(); key != "KEY_ONE" {
+		t.Errorf("expected: KEY_ONE, got: %s", key)
+	}
+}
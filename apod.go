@@ -1,10 +1,24 @@
 package nasa
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"net/url"
+	"strings"
 )
 
-const apodAPIURL = "https://api.nasa.gov/planetary/apod"
+// APOD media types.
+const (
+	APODMediaImage = "image"
+	APODMediaVideo = "video"
+)
+
+// Video providers recognized by APODImage.Video.
+const (
+	VideoProviderYouTube = "youtube"
+	VideoProviderVimeo   = "vimeo"
+)
 
 // APODImage represents an Astronomy Picture Of the Day.
 type APODImage struct {
@@ -12,15 +26,149 @@ type APODImage struct {
 	Title          string `json:"title"`
 	URL            string `json:"url"`
 	HDURL          string `json:"hdurl"`
+	ThumbnailURL   string `json:"thumbnail_url,omitempty"`
 	Explanation    string `json:"explanation"`
 	MediaType      string `json:"media_type"`
 	Copyright      string `json:"copyright"`
 	ServiceVersion string `json:"service_version"`
 }
 
+// IsImage reports whether the APOD is an image.
+func (i APODImage) IsImage() bool {
+	return i.MediaType == APODMediaImage
+}
+
+// IsVideo reports whether the APOD is a video. URL is then usually an embed
+// URL, and ThumbnailURL is set if the APOD was requested with Thumbs.
+func (i APODImage) IsVideo() bool {
+	return i.MediaType == APODMediaVideo
+}
+
+// APODVideo describes the video of an APOD.
+type APODVideo struct {
+	// Provider is VideoProviderYouTube, VideoProviderVimeo, or empty if the
+	// video is hosted elsewhere.
+	Provider string
+
+	// ID is the provider's video ID.
+	ID string
+
+	// WatchURL is the provider's page for the video.
+	WatchURL string
+
+	// EmbedURL is the URL of the embeddable player.
+	EmbedURL string
+}
+
+// Video parses the video of the APOD. It returns false if the APOD is not a video.
+// Videos from unknown providers are returned with only the URLs set to URL.
+func (i APODImage) Video() (APODVideo, bool) {
+	if !i.IsVideo() {
+		return APODVideo{}, false
+	}
+
+	v := parseVideoURL(i.URL)
+	if v.Provider == "" {
+		v.WatchURL = i.URL
+		v.EmbedURL = i.URL
+	}
+	return v, true
+}
+
+func parseVideoURL(s string) APODVideo {
+	// Some APODs use protocol-relative URLs.
+	if strings.HasPrefix(s, "//") {
+		s = "https:" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return APODVideo{}
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	path := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	id := ""
+	switch host {
+	case "youtube.com", "m.youtube.com", "youtube-nocookie.com":
+		switch {
+		case len(path) == 1 && path[0] == "watch":
+			id = u.Query().Get("v")
+		case len(path) == 2 && (path[0] == "embed" || path[0] == "v"):
+			id = path[1]
+		}
+		if id != "" {
+			return youTubeVideo(id)
+		}
+	case "youtu.be":
+		if id = path[0]; id != "" {
+			return youTubeVideo(id)
+		}
+	case "vimeo.com", "player.vimeo.com":
+		// vimeo.com/{id} or player.vimeo.com/video/{id}
+		if len(path) == 2 && path[0] == "video" {
+			id = path[1]
+		} else if len(path) == 1 {
+			id = path[0]
+		}
+		if isDigits(id) {
+			return APODVideo{
+				Provider: VideoProviderVimeo,
+				ID:       id,
+				WatchURL: "https://vimeo.com/" + id,
+				EmbedURL: "https://player.vimeo.com/video/" + id,
+			}
+		}
+	}
+
+	return APODVideo{}
+}
+
+func youTubeVideo(id string) APODVideo {
+	return APODVideo{
+		Provider: VideoProviderYouTube,
+		ID:       id,
+		WatchURL: "https://www.youtube.com/watch?v=" + id,
+		EmbedURL: "https://www.youtube.com/embed/" + id,
+	}
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // APOD returns the Astronomy Picture Of the Day.
 func APOD(p ParamEncoder) (APODImage, error) {
-	content, err := getContent(apodAPIURL, p)
+	return DefaultClient.APOD(p)
+}
+
+// APODContext is like APOD but uses the given context.
+func APODContext(ctx context.Context, p ParamEncoder) (APODImage, error) {
+	return DefaultClient.APODContext(ctx, p)
+}
+
+// APOD returns the Astronomy Picture Of the Day. Params selecting a range
+// or count of APODs return ErrorAPODMultiple; use APODList for those.
+func (c *Client) APOD(p ParamEncoder) (APODImage, error) {
+	return c.APODContext(context.Background(), p)
+}
+
+// APODContext is like APOD but uses the given context.
+func (c *Client) APODContext(ctx context.Context, p ParamEncoder) (APODImage, error) {
+	if params, ok := p.(*APODParams); ok && params.multiple() {
+		return APODImage{}, ErrorAPODMultiple
+	}
+
+	content, err := c.getContent(ctx, EndpointAPOD, c.apodURL, p)
 	if err != nil {
 		return APODImage{}, err
 	}
@@ -33,3 +181,47 @@ func APOD(p ParamEncoder) (APODImage, error) {
 
 	return img, nil
 }
+
+// APODList returns the Astronomy Pictures Of the Day for a date range or a
+// random count, as set by APODParams StartDate/EndDate or Count.
+func APODList(p ParamEncoder) ([]APODImage, error) {
+	return DefaultClient.APODList(p)
+}
+
+// APODListContext is like APODList but uses the given context.
+func APODListContext(ctx context.Context, p ParamEncoder) ([]APODImage, error) {
+	return DefaultClient.APODListContext(ctx, p)
+}
+
+// APODList returns the Astronomy Pictures Of the Day for a date range or a
+// random count, as set by APODParams StartDate/EndDate or Count.
+func (c *Client) APODList(p ParamEncoder) ([]APODImage, error) {
+	return c.APODListContext(context.Background(), p)
+}
+
+// APODListContext is like APODList but uses the given context.
+func (c *Client) APODListContext(ctx context.Context, p ParamEncoder) ([]APODImage, error) {
+	content, err := c.getContent(ctx, EndpointAPOD, c.apodURL, p)
+	if err != nil {
+		return []APODImage{}, err
+	}
+
+	// Without a range or count the API returns a single object.
+	content = bytes.TrimSpace(content)
+	if len(content) > 0 && content[0] == '{' {
+		img := APODImage{}
+		err = json.Unmarshal(content, &img)
+		if err != nil {
+			return []APODImage{}, err
+		}
+		return []APODImage{img}, nil
+	}
+
+	images := []APODImage{}
+	err = json.Unmarshal(content, &images)
+	if err != nil {
+		return []APODImage{}, err
+	}
+
+	return images, nil
+}
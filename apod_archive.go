@@ -0,0 +1,195 @@
+package nasa
+
+import (
+	"context"
+	"time"
+)
+
+// apodDefaultChunkDays is how many days the archive iterator requests at once.
+const apodDefaultChunkDays = 31
+
+// APODGapDays are the days between APODFirstDate and today with no APOD.
+// The archive iterator never requests them.
+var APODGapDays = []time.Time{
+	time.Date(1995, 6, 17, 0, 0, 0, 0, time.UTC),
+	time.Date(1995, 6, 18, 0, 0, 0, 0, time.UTC),
+	time.Date(1995, 6, 19, 0, 0, 0, 0, time.UTC),
+}
+
+// APODArchiveParams configures an APODIterator.
+type APODArchiveParams struct {
+	APIKey string
+
+	// Start is the first date to fetch; use a previous Checkpoint to resume.
+	// It defaults to APODFirstDate.
+	Start time.Time
+
+	// End is the last date to fetch. It defaults to today.
+	End time.Time
+
+	// ChunkDays is how many days are requested at once. It defaults to 31.
+	ChunkDays int
+
+	// Thumbs requests thumbnail URLs for video entries.
+	Thumbs bool
+}
+
+// APODIterator walks the APOD archive in date order, fetching it in chunks.
+// Running out of quota is handled by the RateLimitPolicy of the client: use
+// RateLimitWait to have long walks pause until the quota is restored.
+//
+//	it := client.APODArchive(&nasa.APODArchiveParams{Start: checkpoint})
+//	for it.Next() {
+//		img := it.Image()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		// Resume later from it.Checkpoint().
+//	}
+type APODIterator struct {
+	c   *Client
+	ctx context.Context
+	p   APODArchiveParams
+
+	pos  time.Time
+	end  time.Time
+	buf  []APODImage
+	cur  APODImage
+	next time.Time
+	err  error
+}
+
+// APODArchive returns an iterator over the APOD archive.
+func APODArchive(p *APODArchiveParams) *APODIterator {
+	return DefaultClient.APODArchive(p)
+}
+
+// APODArchiveContext is like APODArchive but uses the given context.
+func APODArchiveContext(ctx context.Context, p *APODArchiveParams) *APODIterator {
+	return DefaultClient.APODArchiveContext(ctx, p)
+}
+
+// APODArchive returns an iterator over the APOD archive.
+func (c *Client) APODArchive(p *APODArchiveParams) *APODIterator {
+	return c.APODArchiveContext(context.Background(), p)
+}
+
+// APODArchiveContext is like APODArchive but uses the given context.
+func (c *Client) APODArchiveContext(ctx context.Context, p *APODArchiveParams) *APODIterator {
+	if p == nil {
+		p = &APODArchiveParams{}
+	}
+	it := &APODIterator{c: c, ctx: ctx, p: *p}
+
+	if it.p.ChunkDays <= 0 {
+		it.p.ChunkDays = apodDefaultChunkDays
+	}
+
+	it.pos = truncateDay(it.p.Start)
+	if it.pos.Before(APODFirstDate) {
+		it.pos = APODFirstDate
+	}
+
+	it.end = apodLatestDate()
+	if !it.p.End.IsZero() && truncateDay(it.p.End).Before(it.end) {
+		it.end = truncateDay(it.p.End)
+	}
+
+	it.next = it.pos
+
+	return it
+}
+
+// apodLatestDate returns the latest date the API is sure to accept, at
+// midnight UTC. APOD days roll over in US Eastern time.
+func apodLatestDate() time.Time {
+	return truncateDay(time.Now().In(time.FixedZone("EST", -5*60*60)))
+}
+
+// truncateDay returns the calendar date of t at midnight UTC.
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// IsAPODGapDay reports whether the calendar date of t is one of APODGapDays.
+func IsAPODGapDay(t time.Time) bool {
+	day := truncateDay(t)
+	for _, g := range APODGapDays {
+		if truncateDay(g).Equal(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next advances to the next APOD, fetching another chunk if needed. It
+// returns false when the archive is exhausted or an error occurred.
+func (it *APODIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.err != nil || it.pos.After(it.end) {
+			return false
+		}
+		it.fetch()
+	}
+
+	it.cur = it.buf[0]
+	it.buf = it.buf[1:]
+	it.next = truncateDay(it.cur.Date.Time).AddDate(0, 0, 1)
+
+	return true
+}
+
+// fetch requests the next chunk, never including a gap day.
+func (it *APODIterator) fetch() {
+	for IsAPODGapDay(it.pos) {
+		it.pos = it.pos.AddDate(0, 0, 1)
+	}
+	if it.pos.After(it.end) {
+		return
+	}
+
+	to := it.pos
+	for i := 1; i < it.p.ChunkDays; i++ {
+		d := to.AddDate(0, 0, 1)
+		if d.After(it.end) || IsAPODGapDay(d) {
+			break
+		}
+		to = d
+	}
+
+	images, err := it.c.APODListContext(it.ctx, &APODParams{
+		APIKey:    it.p.APIKey,
+		StartDate: it.pos,
+		EndDate:   to,
+		Thumbs:    it.p.Thumbs,
+	})
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	it.buf = images
+	it.pos = to.AddDate(0, 0, 1)
+
+	// Nothing more to yield; the checkpoint moves past the empty range.
+	if len(images) == 0 {
+		it.next = it.pos
+	}
+}
+
+// Image returns the current APOD.
+func (it *APODIterator) Image() APODImage {
+	return it.cur
+}
+
+// Err returns the error which stopped the iterator, if any.
+func (it *APODIterator) Err() error {
+	return it.err
+}
+
+// Checkpoint returns the date to pass as Start to resume after the last
+// APOD returned by Image.
+func (it *APODIterator) Checkpoint() time.Time {
+	return it.next
+}
@@ -0,0 +1,298 @@
+package nasa
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// APODDownloadOptions configures an APOD download.
+type APODDownloadOptions struct {
+	// SD downloads URL rather than HDURL.
+	SD bool
+
+	// Progress, if set, is called as the image is written with the number of
+	// bytes written so far, including a resumed part, and the total size or
+	// -1 if it is unknown.
+	Progress func(written, total int64)
+}
+
+// APODDownload describes a downloaded APOD image.
+type APODDownload struct {
+	// URL is the image URL which was downloaded.
+	URL string
+
+	// Path is the file the image was written to, if any.
+	Path string
+
+	// ContentType is the media type of the image, such as "image/jpeg".
+	ContentType string
+
+	// Ext is the file extension for ContentType, such as ".jpg".
+	Ext string
+
+	// Size is the size of the image in bytes.
+	Size int64
+
+	// Resumed reports whether a partial download was continued.
+	Resumed bool
+}
+
+var imageExts = map[string]string{
+	"image/bmp":  ".bmp",
+	"image/gif":  ".gif",
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/tiff": ".tif",
+	"image/webp": ".webp",
+}
+
+// DownloadAPOD writes the image of img to w.
+func DownloadAPOD(img *APODImage, w io.Writer, opts *APODDownloadOptions) (APODDownload, error) {
+	return DefaultClient.DownloadAPOD(img, w, opts)
+}
+
+// DownloadAPODContext is like DownloadAPOD but uses the given context.
+func DownloadAPODContext(ctx context.Context, img *APODImage, w io.Writer, opts *APODDownloadOptions) (APODDownload, error) {
+	return DefaultClient.DownloadAPODContext(ctx, img, w, opts)
+}
+
+// DownloadAPODFile writes the image of img to the file at path.
+func DownloadAPODFile(img *APODImage, path string, opts *APODDownloadOptions) (APODDownload, error) {
+	return DefaultClient.DownloadAPODFile(img, path, opts)
+}
+
+// DownloadAPODFileContext is like DownloadAPODFile but uses the given context.
+func DownloadAPODFileContext(ctx context.Context, img *APODImage, path string, opts *APODDownloadOptions) (APODDownload, error) {
+	return DefaultClient.DownloadAPODFileContext(ctx, img, path, opts)
+}
+
+// DownloadAPOD writes the image of img to w. HDURL is downloaded unless
+// opts.SD is set, falling back to URL if it is missing or not found.
+// ErrorAPODNoImage is returned for videos and for responses which are not images.
+func (c *Client) DownloadAPOD(img *APODImage, w io.Writer, opts *APODDownloadOptions) (APODDownload, error) {
+	return c.DownloadAPODContext(context.Background(), img, w, opts)
+}
+
+// DownloadAPODContext is like DownloadAPOD but uses the given context.
+func (c *Client) DownloadAPODContext(ctx context.Context, img *APODImage, w io.Writer, opts *APODDownloadOptions) (APODDownload, error) {
+	if opts == nil {
+		opts = &APODDownloadOptions{}
+	}
+
+	resp, u, err := c.openAPOD(ctx, img, opts, 0)
+	if err != nil {
+		return APODDownload{}, err
+	}
+	defer resp.Body.Close()
+
+	d, body, err := inspectAPOD(resp, u, 0)
+	if err != nil {
+		return d, err
+	}
+
+	return d, copyAPOD(&d, w, body, resp, opts)
+}
+
+// DownloadAPODFile writes the image of img to the file at path, like
+// DownloadAPOD. If path has no extension the detected one is added.
+//
+// The image is written to path with a ".part" suffix and renamed once
+// complete, so path never holds a partial image. An interrupted download is
+// resumed from the ".part" file with a Range request.
+func (c *Client) DownloadAPODFile(img *APODImage, path string, opts *APODDownloadOptions) (APODDownload, error) {
+	return c.DownloadAPODFileContext(context.Background(), img, path, opts)
+}
+
+// DownloadAPODFileContext is like DownloadAPODFile but uses the given context.
+func (c *Client) DownloadAPODFileContext(ctx context.Context, img *APODImage, path string, opts *APODDownloadOptions) (APODDownload, error) {
+	if opts == nil {
+		opts = &APODDownloadOptions{}
+	}
+
+	part := path + ".part"
+
+	var offset int64
+	if fi, err := os.Stat(part); err == nil {
+		offset = fi.Size()
+	}
+
+	resp, u, err := c.openAPOD(ctx, img, opts, offset)
+	apiErr := &APIError{}
+	if offset > 0 && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		// The partial file is unusable; start over.
+		offset = 0
+		resp, u, err = c.openAPOD(ctx, img, opts, 0)
+	}
+	if err != nil {
+		return APODDownload{}, err
+	}
+	defer resp.Body.Close()
+
+	d, body, err := inspectAPOD(resp, u, offset)
+	if err != nil {
+		return d, err
+	}
+
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if d.Resumed {
+		flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	f, err := os.OpenFile(part, flag, 0644)
+	if err != nil {
+		return d, err
+	}
+
+	err = copyAPOD(&d, f, body, resp, opts)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		// The partial file is kept so the download can be resumed.
+		return d, err
+	}
+
+	if filepath.Ext(path) == "" {
+		path += d.Ext
+	}
+	d.Path = path
+
+	return d, os.Rename(part, path)
+}
+
+// downloadURLs returns the image URLs to try, in order.
+func (i *APODImage) downloadURLs(sd bool) []string {
+	if i.IsVideo() {
+		return nil
+	}
+
+	urls := []string{}
+	if !sd && i.HDURL != "" {
+		urls = append(urls, i.HDURL)
+	}
+	if i.URL != "" && i.URL != i.HDURL {
+		urls = append(urls, i.URL)
+	}
+	return urls
+}
+
+// openAPOD requests the first image URL of img which is found. A non-zero
+// offset is requested with a Range header from the first URL only.
+func (c *Client) openAPOD(ctx context.Context, img *APODImage, opts *APODDownloadOptions, offset int64) (*http.Response, string, error) {
+	urls := img.downloadURLs(opts.SD)
+	if len(urls) == 0 {
+		return nil, "", ErrorAPODNoImage
+	}
+
+	var err error
+	for i, u := range urls {
+		req, rerr := c.newRequest(ctx, u)
+		if rerr != nil {
+			return nil, "", rerr
+		}
+
+		if i == 0 && offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+
+		var resp *http.Response
+		resp, err = c.open(req)
+		if err == nil {
+			return resp, u, nil
+		}
+		if !errors.Is(err, ErrorNotFound) {
+			return nil, "", err
+		}
+	}
+
+	return nil, "", err
+}
+
+// inspectAPOD checks resp is an image, continuing from offset if it is a
+// partial response, and returns the body to copy it from.
+func inspectAPOD(resp *http.Response, u string, offset int64) (APODDownload, io.Reader, error) {
+	d := APODDownload{URL: u}
+	body := bufio.NewReader(resp.Body)
+
+	if resp.StatusCode == http.StatusPartialContent {
+		if !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return d, nil, fmt.Errorf("%s: unexpected Content-Range %q", u, resp.Header.Get("Content-Range"))
+		}
+		d.Resumed = true
+		d.Size = offset
+	}
+
+	d.ContentType, _, _ = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if (d.ContentType == "" || d.ContentType == "application/octet-stream") && !d.Resumed {
+		b, _ := body.Peek(512)
+		d.ContentType, _, _ = mime.ParseMediaType(http.DetectContentType(b))
+	}
+
+	if d.ContentType != "" && d.ContentType != "application/octet-stream" &&
+		!strings.HasPrefix(d.ContentType, "image/") {
+		return d, nil, fmt.Errorf("%s: %w: got %s", u, ErrorAPODNoImage, d.ContentType)
+	}
+
+	d.Ext = imageExts[d.ContentType]
+	if d.Ext == "" {
+		d.Ext = strings.ToLower(path.Ext(urlPath(u)))
+		if d.Ext == ".jpeg" {
+			d.Ext = ".jpg"
+		}
+	}
+
+	return d, body, nil
+}
+
+func urlPath(u string) string {
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	return u
+}
+
+// copyAPOD copies body to w, reporting progress, and checks the whole image arrived.
+func copyAPOD(d *APODDownload, w io.Writer, body io.Reader, resp *http.Response, opts *APODDownloadOptions) error {
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = d.Size + resp.ContentLength
+	}
+
+	pw := &progressWriter{w: w, written: d.Size, total: total, progress: opts.Progress}
+	_, err := io.Copy(pw, body)
+	d.Size = pw.written
+	if err != nil {
+		return err
+	}
+
+	if total >= 0 && d.Size != total {
+		return fmt.Errorf("%s: %w: got %d of %d bytes", d.URL, io.ErrUnexpectedEOF, d.Size, total)
+	}
+
+	return nil
+}
+
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	progress func(written, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if p.progress != nil {
+		p.progress(p.written, p.total)
+	}
+	return n, err
+}
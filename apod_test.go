@@ -0,0 +1,365 @@
+package nasa
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPODList(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start_date") == "" {
+			w.Write([]byte(`{"date":"2020-04-29","title":"Single"}`))
+			return
+		}
+		w.Write([]byte(`[{"date":"2020-04-28","title":"First"},{"date":"2020-04-29","title":"Second"}]`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithAPODURL(ts.URL), WithAPIKey("NASA_KEY"))
+
+	t.Run("range", func(t *testing.T) {
+		p := &APODParams{
+			StartDate: time.Date(2020, 4, 28, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2020, 4, 29, 0, 0, 0, 0, time.UTC),
+		}
+
+		images, err := c.APODList(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(images) != 2 || images[0].Title != "First" || images[1].Title != "Second" {
+			t.Errorf("unexpected images: %+v", images)
+		}
+	})
+
+	t.Run("single", func(t *testing.T) {
+		images, err := c.APODList(&APODParams{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(images) != 1 || images[0].Title != "Single" {
+			t.Errorf("unexpected images: %+v", images)
+		}
+	})
+}
+
+func TestAPODMultiple(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithAPODURL(ts.URL), WithAPIKey("NASA_KEY"))
+	for _, p := range []*APODParams{
+		{StartDate: time.Date(2020, 4, 27, 0, 0, 0, 0, time.UTC)},
+		{Count: 3},
+	} {
+		if _, err := c.APOD(p); err != ErrorAPODMultiple {
+			t.Errorf("wrong error returned: %v", err)
+		}
+	}
+
+	if requests != 0 {
+		t.Errorf("expected: 0 requests, got: %d", requests)
+	}
+}
+
+func TestAPODArchive(t *testing.T) {
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		ranges = append(ranges, q.Get("start_date")+"/"+q.Get("end_date"))
+
+		start, _ := time.Parse("2006-01-02", q.Get("start_date"))
+		end, _ := time.Parse("2006-01-02", q.Get("end_date"))
+		if end.After(time.Date(1995, 6, 25, 0, 0, 0, 0, time.UTC)) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		images := []string{}
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			images = append(images, `{"date":"`+d.Format("2006-01-02")+`"}`)
+		}
+		w.Write([]byte("[" + strings.Join(images, ",") + "]"))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithAPODURL(ts.URL), WithAPIKey("NASA_KEY"))
+
+	t.Run("chunks around gaps", func(t *testing.T) {
+		ranges = nil
+		it := c.APODArchive(&APODArchiveParams{
+			End:       time.Date(1995, 6, 24, 0, 0, 0, 0, time.UTC),
+			ChunkDays: 3,
+		})
+
+		var dates []string
+		for it.Next() {
+			dates = append(dates, it.Image().Date.Format("2006-01-02"))
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "1995-06-16 1995-06-20 1995-06-21 1995-06-22 1995-06-23 1995-06-24"
+		if got := strings.Join(dates, " "); got != expected {
+			t.Errorf("expected: %s, got: %s", expected, got)
+		}
+
+		expected = "1995-06-16/1995-06-16 1995-06-20/1995-06-22 1995-06-23/1995-06-24"
+		if got := strings.Join(ranges, " "); got != expected {
+			t.Errorf("expected: %s, got: %s", expected, got)
+		}
+
+		checkpoint := it.Checkpoint().Format("2006-01-02")
+		if checkpoint != "1995-06-25" {
+			t.Errorf("expected: 1995-06-25, got: %s", checkpoint)
+		}
+	})
+
+	t.Run("nil params", func(t *testing.T) {
+		it := c.APODArchive(nil)
+		if !it.Next() {
+			t.Fatal(it.Err())
+		}
+
+		if d := it.Image().Date.Format("2006-01-02"); d != "1995-06-16" {
+			t.Errorf("expected: 1995-06-16, got: %s", d)
+		}
+	})
+
+	t.Run("resume from checkpoint", func(t *testing.T) {
+		it := c.APODArchive(&APODArchiveParams{
+			Start:     time.Date(1995, 6, 22, 0, 0, 0, 0, time.UTC),
+			End:       time.Date(1995, 6, 30, 0, 0, 0, 0, time.UTC),
+			ChunkDays: 2,
+		})
+
+		n := 0
+		for it.Next() {
+			n++
+		}
+
+		if n != 4 {
+			t.Errorf("expected 4 images, got: %d", n)
+		}
+
+		if it.Err() == nil {
+			t.Error("expected an error")
+		}
+
+		checkpoint := it.Checkpoint().Format("2006-01-02")
+		if checkpoint != "1995-06-26" {
+			t.Errorf("expected: 1995-06-26, got: %s", checkpoint)
+		}
+	})
+}
+
+func TestAPODVideo(t *testing.T) {
+	tests := []struct {
+		url      string
+		provider string
+		id       string
+		watch    string
+		embed    string
+	}{
+		{
+			"https://www.youtube.com/embed/dQw4w9WgXcQ?rel=0",
+			VideoProviderYouTube, "dQw4w9WgXcQ",
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			"https://www.youtube.com/embed/dQw4w9WgXcQ",
+		},
+		{
+			"//www.youtube-nocookie.com/embed/dQw4w9WgXcQ",
+			VideoProviderYouTube, "dQw4w9WgXcQ",
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			"https://www.youtube.com/embed/dQw4w9WgXcQ",
+		},
+		{
+			"https://youtu.be/dQw4w9WgXcQ",
+			VideoProviderYouTube, "dQw4w9WgXcQ",
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			"https://www.youtube.com/embed/dQw4w9WgXcQ",
+		},
+		{
+			"https://player.vimeo.com/video/123456789?color=ffffff",
+			VideoProviderVimeo, "123456789",
+			"https://vimeo.com/123456789",
+			"https://player.vimeo.com/video/123456789",
+		},
+		{
+			"https://apod.nasa.gov/apod/image/2004/fixture.mp4",
+			"", "",
+			"https://apod.nasa.gov/apod/image/2004/fixture.mp4",
+			"https://apod.nasa.gov/apod/image/2004/fixture.mp4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			v, ok := APODImage{MediaType: "video", URL: tt.url}.Video()
+			if !ok {
+				t.Fatal("expected a video")
+			}
+
+			expected := APODVideo{Provider: tt.provider, ID: tt.id, WatchURL: tt.watch, EmbedURL: tt.embed}
+			if v != expected {
+				t.Errorf("expected: %+v, got: %+v", expected, v)
+			}
+		})
+	}
+
+	t.Run("image", func(t *testing.T) {
+		img := APODImage{MediaType: "image", URL: "https://apod.nasa.gov/apod/image/2004/fixture.jpg"}
+		if !img.IsImage() || img.IsVideo() {
+			t.Error("expected an image")
+		}
+
+		if _, ok := img.Video(); ok {
+			t.Error("expected no video")
+		}
+	})
+}
+
+func TestDownloadAPOD(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 1024)...)
+
+	var ranges []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		switch r.URL.Path {
+		case "/hd.png":
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(png))
+		case "/page.html":
+			w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient()
+
+	t.Run("fallback to URL", func(t *testing.T) {
+		img := &APODImage{MediaType: "image", HDURL: ts.URL + "/missing.jpg", URL: ts.URL + "/hd.png"}
+
+		var progress int64
+		buf := &bytes.Buffer{}
+		d, err := c.DownloadAPOD(img, buf, &APODDownloadOptions{
+			Progress: func(written, total int64) { progress = written },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if d.URL != img.URL || d.ContentType != "image/png" || d.Ext != ".png" {
+			t.Errorf("unexpected download: %+v", d)
+		}
+
+		if !bytes.Equal(buf.Bytes(), png) || d.Size != int64(len(png)) || progress != d.Size {
+			t.Errorf("expected %d bytes, got: %d (progress %d)", len(png), buf.Len(), progress)
+		}
+	})
+
+	t.Run("resume file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "nasa-apod")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "apod")
+		if err := ioutil.WriteFile(path+".part", png[:100], 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		ranges = nil
+		d, err := c.DownloadAPODFile(&APODImage{HDURL: ts.URL + "/hd.png"}, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !d.Resumed || d.Path != path+".png" || len(ranges) != 1 || ranges[0] != "bytes=100-" {
+			t.Errorf("unexpected download: %+v, ranges: %v", d, ranges)
+		}
+
+		b, err := ioutil.ReadFile(d.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(b, png) {
+			t.Errorf("expected %d bytes, got: %d", len(png), len(b))
+		}
+
+		if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+			t.Errorf("expected partial file to be removed, got: %v", err)
+		}
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		for _, img := range []*APODImage{
+			{MediaType: "video", URL: "https://www.youtube.com/embed/dQw4w9WgXcQ"},
+			{MediaType: "image", URL: ts.URL + "/page.html"},
+		} {
+			_, err := c.DownloadAPOD(img, ioutil.Discard, nil)
+			if !errors.Is(err, ErrorAPODNoImage) {
+				t.Errorf("expected ErrorAPODNoImage, got: %v", err)
+			}
+		}
+	})
+}
+
+func TestAPODArchiveRateLimit(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "30")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+
+		q := r.URL.Query()
+		w.Write([]byte(`[{"date":"` + q.Get("start_date") + `"}]`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithAPODURL(ts.URL), WithAPIKey("NASA_KEY"), WithRateLimitPolicy(RateLimitFailFast))
+	it := c.APODArchive(&APODArchiveParams{
+		Start:     time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:       time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+		ChunkDays: 1,
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for it.Next() {
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("iterator blocked on the exhausted key")
+	}
+
+	rlErr := &RateLimitError{}
+	if !errors.As(it.Err(), &rlErr) {
+		t.Errorf("expected *RateLimitError, got: %v", it.Err())
+	}
+	if requests != 1 {
+		t.Errorf("expected: 1 request, got: %d", requests)
+	}
+}
@@ -0,0 +1,439 @@
+package nasa
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CacheForever is a CacheTTL result for responses which never change.
+const CacheForever time.Duration = -1
+
+// Cache stores API responses. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the entry stored under key, if any.
+	Get(key string) (*CacheEntry, bool)
+
+	// Set stores the entry under key.
+	Set(key string, e *CacheEntry)
+
+	// Delete removes the entry stored under key.
+	Delete(key string)
+}
+
+// CacheEntry is a cached API response.
+type CacheEntry struct {
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Expires      time.Time `json:"expires"`
+}
+
+// Fresh reports whether the entry can be used without revalidation.
+func (e *CacheEntry) Fresh() bool {
+	return e.Expires.IsZero() || time.Now().Before(e.Expires)
+}
+
+// CacheTTL returns how long a response from endpoint e for u stays fresh.
+// CacheForever (or any negative duration) caches it forever; zero disables caching.
+type CacheTTL func(e Endpoint, u *url.URL) time.Duration
+
+// WithCache sets the cache used to store responses.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithCacheTTL sets the rules deciding how long cached responses stay fresh.
+// A nil ttl keeps the default rules.
+func WithCacheTTL(ttl CacheTTL) Option {
+	return func(c *Client) {
+		c.cacheTTL = ttl
+	}
+}
+
+var (
+	epicDatePath  = regexp.MustCompile(`/date/(\d{4}-\d{2}-\d{2})`)
+	marsRoverPath = regexp.MustCompile(`/(?:rovers|manifests)/([^/]+)`)
+)
+
+// marsSettledSols is how many sols behind a rover's latest sol its photos
+// are taken to be complete.
+const marsSettledSols = 30
+
+// DefaultCacheTTL caches responses for past dates forever and everything
+// which may still change for a short time.
+//
+// A client left with the default rules also caches Mars photos of a sol
+// forever once it is 30 sols behind the latest sol of the rover, as seen in
+// the Mars responses of that client.
+func DefaultCacheTTL(e Endpoint, u *url.URL) time.Duration {
+	return defaultCacheTTL(e, u, nil)
+}
+
+// defaultCacheTTL implements DefaultCacheTTL. maxSol, if not nil, returns
+// the latest known sol of the rover requested by u.
+func defaultCacheTTL(e Endpoint, u *url.URL, maxSol func(u *url.URL) (int, bool)) time.Duration {
+	q := u.Query()
+
+	switch e {
+	case EndpointAPOD:
+		if q.Get("count") != "" {
+			// Random picks are different every time.
+			return 0
+		}
+		if isPastDate(q.Get("date"), 0) || isPastDate(q.Get("end_date"), 0) {
+			return CacheForever
+		}
+		return time.Hour
+	case EndpointEPIC:
+		if m := epicDatePath.FindStringSubmatch(u.Path); m != nil && isPastDate(m[1], 7) {
+			return CacheForever
+		}
+		return time.Hour
+	case EndpointMarsPhotos:
+		if isPastDate(q.Get("earth_date"), 30) || isSettledSol(u, maxSol) {
+			return CacheForever
+		}
+		return 6 * time.Hour
+	case EndpointMarsLatestPhotos, EndpointMarsManifest:
+		return time.Hour
+	case EndpointMediaSearch:
+		return 24 * time.Hour
+	case EndpointMediaAsset, EndpointMediaMetadata, EndpointMediaCaptions:
+		return CacheForever
+	}
+
+	return 0
+}
+
+// isPastDate reports whether the YYYY-MM-DD date is more than days days before yesterday.
+// Yesterday is used so that timezone differences with the API never matter.
+func isPastDate(date string, days int) bool {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return false
+	}
+	return t.Before(time.Now().UTC().AddDate(0, 0, -1-days))
+}
+
+// isSettledSol reports whether u requests Mars photos of a sol well behind
+// the latest sol of the rover given by maxSol.
+func isSettledSol(u *url.URL, maxSol func(u *url.URL) (int, bool)) bool {
+	if maxSol == nil {
+		return false
+	}
+
+	q := u.Query()
+	sol, err := strconv.Atoi(q.Get("sol"))
+	if err != nil || q.Get("earth_date") != "" {
+		return false
+	}
+
+	max, ok := maxSol(u)
+	return ok && sol <= max-marsSettledSols
+}
+
+// ttl returns how long the response from endpoint e for u stays fresh,
+// using the client rules.
+func (c *Client) ttl(e Endpoint, u *url.URL) time.Duration {
+	if c.cacheTTL != nil {
+		return c.cacheTTL(e, u)
+	}
+	return defaultCacheTTL(e, u, c.marsMaxSol)
+}
+
+// marsRoverKey returns the key of the rover requested by u among the
+// learned max sols.
+func (c *Client) marsRoverKey(u *url.URL) (string, bool) {
+	m := marsRoverPath.FindStringSubmatch(u.Path)
+	if m == nil {
+		return "", false
+	}
+	return c.marsURL + " " + m[1], true
+}
+
+// marsMaxSol returns the latest sol of the rover requested by u, as seen in
+// the Mars responses of the client.
+func (c *Client) marsMaxSol(u *url.URL) (int, bool) {
+	key, ok := c.marsRoverKey(u)
+	if !ok {
+		return 0, false
+	}
+
+	c.solMu.Lock()
+	defer c.solMu.Unlock()
+
+	max, ok := c.maxSols[key]
+	return max, ok
+}
+
+// learnMarsMaxSol records the latest sol of the rover given in a Mars
+// response body.
+func (c *Client) learnMarsMaxSol(e Endpoint, u *url.URL, body []byte) {
+	if e != EndpointMarsPhotos && e != EndpointMarsLatestPhotos && e != EndpointMarsManifest {
+		return
+	}
+
+	key, ok := c.marsRoverKey(u)
+	if !ok {
+		return
+	}
+
+	type rover struct {
+		Rover struct {
+			MaxSol int `json:"max_sol"`
+		} `json:"rover"`
+	}
+	r := struct {
+		Photos       []rover `json:"photos"`
+		LatestPhotos []rover `json:"latest_photos"`
+		Manifest     struct {
+			MaxSol int `json:"max_sol"`
+		} `json:"photo_manifest"`
+	}{}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return
+	}
+
+	max := r.Manifest.MaxSol
+	for _, p := range append(r.Photos, r.LatestPhotos...) {
+		if p.Rover.MaxSol > max {
+			max = p.Rover.MaxSol
+		}
+	}
+	if max == 0 {
+		return
+	}
+
+	c.solMu.Lock()
+	defer c.solMu.Unlock()
+
+	if c.maxSols == nil {
+		c.maxSols = make(map[string]int)
+	}
+	if max > c.maxSols[key] {
+		c.maxSols[key] = max
+	}
+}
+
+// cacheKey returns the key for req; the API key is left out so all keys share entries.
+func cacheKey(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	q.Del("api_key")
+	u.RawQuery = q.Encode()
+	return req.Method + " " + u.String()
+}
+
+// doCached serves req from the client cache, revalidating stale entries.
+func (c *Client) doCached(req *http.Request, e Endpoint) ([]byte, error) {
+	ttl := c.ttl(e, req.URL)
+	if ttl == 0 {
+		content, _, err := c.do(req)
+		return content, err
+	}
+
+	key := cacheKey(req)
+	entry, ok := c.cache.Get(key)
+	if ok && entry.Fresh() {
+		return entry.Body, nil
+	}
+
+	if ok {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	content, resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		content = entry.Body
+	}
+
+	// The response may tell how long it stays fresh, such as the latest
+	// sol of a rover.
+	c.learnMarsMaxSol(e, req.URL, content)
+	ttl = c.ttl(e, req.URL)
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		entry.Expires = expires(ttl)
+		c.cache.Set(key, entry)
+		return entry.Body, nil
+	}
+
+	c.cache.Set(key, &CacheEntry{
+		Body:         content,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Expires:      expires(ttl),
+	})
+
+	return content, nil
+}
+
+func expires(ttl time.Duration) time.Time {
+	if ttl < 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// MemoryCache is an in-memory Cache which evicts the least recently used entries.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache returns a MemoryCache holding at most maxEntries entries.
+// If maxEntries is zero there is no limit.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry stored under key, if any.
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	m.ll.MoveToFront(el)
+
+	e := *el.Value.(*memoryCacheItem).entry
+	return &e, true
+}
+
+// Set stores the entry under key.
+func (m *MemoryCache) Set(key string, e *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		m.ll.MoveToFront(el)
+		el.Value.(*memoryCacheItem).entry = e
+		return
+	}
+
+	m.items[key] = m.ll.PushFront(&memoryCacheItem{key: key, entry: e})
+
+	if m.maxEntries > 0 && m.ll.Len() > m.maxEntries {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// Delete removes the entry stored under key.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[key]; ok {
+		m.ll.Remove(el)
+		delete(m.items, key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+// DiskCache is a Cache which stores each entry as a file in a directory.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a DiskCache storing entries in dir, creating it if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the entry stored under key, if any.
+func (d *DiskCache) Get(key string) (*CacheEntry, bool) {
+	b, err := ioutil.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	e := &CacheEntry{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// Set stores the entry under key. Write errors are ignored, leaving the
+// entry uncached.
+func (d *DiskCache) Set(key string, e *CacheEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	f, err := ioutil.TempFile(d.dir, ".tmp-")
+	if err != nil {
+		return
+	}
+
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return
+	}
+
+	if err := os.Rename(f.Name(), d.path(key)); err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// Delete removes the entry stored under key.
+func (d *DiskCache) Delete(key string) {
+	os.Remove(d.path(key))
+}
@@ -0,0 +1,228 @@
+package nasa
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	m := NewMemoryCache(2)
+	m.Set("a", &CacheEntry{Body: []byte("a")})
+	m.Set("b", &CacheEntry{Body: []byte("b")})
+
+	// Use "a" so "b" is the least recently used.
+	if _, ok := m.Get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	m.Set("c", &CacheEntry{Body: []byte("c")})
+
+	if _, ok := m.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+
+	if m.Len() != 2 {
+		t.Errorf("expected 2 entries, got: %d", m.Len())
+	}
+
+	m.Delete("a")
+	if _, ok := m.Get("a"); ok {
+		t.Error("expected a to be deleted")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nasa-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expires := time.Date(2020, 4, 29, 0, 0, 0, 0, time.UTC)
+	d.Set("key", &CacheEntry{Body: []byte(`{"title":"Test"}`), ETag: `"abc"`, Expires: expires})
+
+	e, ok := d.Get("key")
+	if !ok {
+		t.Fatal("expected entry to be cached")
+	}
+
+	if string(e.Body) != `{"title":"Test"}` || e.ETag != `"abc"` || !e.Expires.Equal(expires) {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+
+	d.Delete("key")
+	if _, ok := d.Get("key"); ok {
+		t.Error("expected entry to be deleted")
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	requests := 0
+	revalidated := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"title":"Test APOD"}`))
+	}))
+	defer ts.Close()
+
+	ttl := CacheForever
+	c := NewClient(
+		WithAPODURL(ts.URL),
+		WithCache(NewMemoryCache(0)),
+		WithCacheTTL(func(e Endpoint, u *url.URL) time.Duration { return ttl }),
+	)
+
+	t.Run("shared between keys", func(t *testing.T) {
+		for _, key := range []string{"KEY_ONE", "KEY_TWO"} {
+			img, err := c.APOD(&APODParams{APIKey: key})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if img.Title != "Test APOD" {
+				t.Errorf("expected: Test APOD, got: %s", img.Title)
+			}
+		}
+
+		if requests != 1 {
+			t.Errorf("expected 1 request, got: %d", requests)
+		}
+	})
+
+	t.Run("revalidated", func(t *testing.T) {
+		ttl = time.Nanosecond
+		d := time.Date(2020, 4, 29, 0, 0, 0, 0, time.UTC)
+
+		for i := 0; i < 2; i++ {
+			time.Sleep(time.Millisecond)
+			img, err := c.APOD(&APODParams{APIKey: "NASA_KEY", Date: d})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if img.Title != "Test APOD" {
+				t.Errorf("expected: Test APOD, got: %s", img.Title)
+			}
+		}
+
+		if revalidated != 1 {
+			t.Errorf("expected 1 revalidation, got: %d", revalidated)
+		}
+	})
+}
+
+func TestDefaultCacheTTL(t *testing.T) {
+	today := time.Now().UTC().Format("2006-01-02")
+
+	tests := []struct {
+		name     string
+		endpoint Endpoint
+		url      string
+		expected time.Duration
+	}{
+		{"APOD today", EndpointAPOD, "https://api.nasa.gov/planetary/apod?date=" + today, time.Hour},
+		{"APOD latest", EndpointAPOD, "https://api.nasa.gov/planetary/apod", time.Hour},
+		{"APOD past", EndpointAPOD, "https://api.nasa.gov/planetary/apod?date=2020-04-29", CacheForever},
+		{"APOD past range", EndpointAPOD, "https://api.nasa.gov/planetary/apod?end_date=2020-04-29&start_date=2020-04-01", CacheForever},
+		{"APOD open range", EndpointAPOD, "https://api.nasa.gov/planetary/apod?start_date=2020-04-01", time.Hour},
+		{"APOD count", EndpointAPOD, "https://api.nasa.gov/planetary/apod?count=5", 0},
+		{"EPIC past", EndpointEPIC, "https://api.nasa.gov/EPIC/api/natural/date/2020-04-24", CacheForever},
+		{"EPIC latest", EndpointEPIC, "https://api.nasa.gov/EPIC/api/natural", time.Hour},
+		{"Mars past", EndpointMarsPhotos, "https://api.nasa.gov/mars-photos/api/v1/rovers/curiosity/photos?earth_date=2015-06-03", CacheForever},
+		{"Mars sol", EndpointMarsPhotos, "https://api.nasa.gov/mars-photos/api/v1/rovers/curiosity/photos?sol=1000", 6 * time.Hour},
+		{"Mars latest", EndpointMarsLatestPhotos, "https://api.nasa.gov/mars-photos/api/v1/rovers/curiosity/latest_photos", time.Hour},
+		{"metadata", EndpointMediaMetadata, "https://images-api.nasa.gov/metadata/PIA12345", CacheForever},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := DefaultCacheTTL(tt.endpoint, u)
+			if got != tt.expected {
+				t.Errorf("expected: %s, got: %s", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("Mars sols learned by the client", func(t *testing.T) {
+		c := NewClient(WithMarsURL("https://api.nasa.gov/mars-photos/api/v1"))
+		other := NewClient(WithMarsURL("http://127.0.0.1/mars-photos/api/v1"))
+
+		manifest, _ := url.Parse("https://api.nasa.gov/mars-photos/api/v1/manifests/curiosity")
+		c.learnMarsMaxSol(EndpointMarsManifest, manifest, []byte(`{"photo_manifest":{"max_sol":3000}}`))
+
+		tests := []struct {
+			name     string
+			c        *Client
+			url      string
+			expected time.Duration
+		}{
+			{"settled sol", c, "https://api.nasa.gov/mars-photos/api/v1/rovers/curiosity/photos?page=2&sol=1000", CacheForever},
+			{"recent sol", c, "https://api.nasa.gov/mars-photos/api/v1/rovers/curiosity/photos?sol=2990", 6 * time.Hour},
+			{"unknown rover", c, "https://api.nasa.gov/mars-photos/api/v1/rovers/spirit/photos?sol=10", 6 * time.Hour},
+			{"other client", other, "http://127.0.0.1/mars-photos/api/v1/rovers/curiosity/photos?sol=1000", 6 * time.Hour},
+		}
+
+		for _, tt := range tests {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := tt.c.ttl(EndpointMarsPhotos, u); got != tt.expected {
+				t.Errorf("%s: expected: %s, got: %s", tt.name, tt.expected, got)
+			}
+		}
+
+		custom := NewClient(WithCacheTTL(func(Endpoint, *url.URL) time.Duration { return time.Minute }))
+		custom.learnMarsMaxSol(EndpointMarsManifest, manifest, []byte(`{"photo_manifest":{"max_sol":3000}}`))
+		u, _ := url.Parse("https://api.nasa.gov/mars-photos/api/v1/rovers/curiosity/photos?sol=1000")
+		if got := custom.ttl(EndpointMarsPhotos, u); got != time.Minute {
+			t.Errorf("expected: 1m0s, got: %s", got)
+		}
+	})
+
+	t.Run("Mars sol learned from response", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"photos":[{"id":1,"sol":100,"rover":{"max_sol":500}}]}`))
+		}))
+		defer ts.Close()
+
+		cache := NewMemoryCache(0)
+		c := NewClient(WithMarsURL(ts.URL), WithAPIKey("NASA_KEY"), WithCache(cache))
+
+		_, err := c.MarsRoverPhotos(&MarsPhotosParams{Sol: 100}, RoverOpportunity)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry, ok := cache.Get("GET " + ts.URL + "/rovers/opportunity/photos?sol=100")
+		if !ok || !entry.Expires.IsZero() {
+			t.Errorf("expected the first response cached forever: %+v", entry)
+		}
+
+		u, _ := url.Parse(ts.URL + "/rovers/opportunity/photos?sol=100")
+		if got := DefaultCacheTTL(EndpointMarsPhotos, u); got != 6*time.Hour {
+			t.Errorf("expected DefaultCacheTTL unaffected, got: %s", got)
+		}
+	})
+}
@@ -0,0 +1,311 @@
+package nasa
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	apodAPIURL  = "https://api.nasa.gov/planetary/apod"
+	epicAPIURL  = "https://api.nasa.gov/EPIC"
+	marsAPIURL  = "https://api.nasa.gov/mars-photos/api/v1"
+	mediaAPIURL = "https://images-api.nasa.gov"
+)
+
+// Endpoint identifies an API endpoint.
+type Endpoint string
+
+// Endpoints requested by the Client.
+const (
+	EndpointAPOD             Endpoint = "apod"
+	EndpointEPIC             Endpoint = "epic"
+	EndpointMarsPhotos       Endpoint = "mars_photos"
+	EndpointMarsLatestPhotos Endpoint = "mars_latest_photos"
+	EndpointMarsManifest     Endpoint = "mars_manifest"
+	EndpointMediaSearch      Endpoint = "media_search"
+	EndpointMediaAsset       Endpoint = "media_asset"
+	EndpointMediaMetadata    Endpoint = "media_metadata"
+	EndpointMediaCaptions    Endpoint = "media_captions"
+)
+
+// DefaultClient is the Client used by the package-level API functions.
+var DefaultClient = NewClient()
+
+// Client makes requests to the NASA APIs.
+type Client struct {
+	httpClient *http.Client
+	userAgent  string
+
+	apiKey          string
+	apiKeys         []string
+	keyIndex        int
+	demoKeyFallback bool
+
+	apodURL        string
+	epicURL        string
+	epicArchiveURL string
+	marsURL        string
+	mediaURL       string
+
+	rateLimitPolicy RateLimitPolicy
+	retryPolicy     RetryPolicy
+	cache           Cache
+	cacheTTL        CacheTTL // nil uses the default rules
+
+	mu            sync.Mutex
+	rateLimits    map[string]RateLimit
+	lastRateLimit RateLimit
+
+	// The latest sol of each rover seen in Mars responses, by Mars URL and
+	// rover slug.
+	solMu   sync.Mutex
+	maxSols map[string]int
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// NewClient returns a Client configured with the given options.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		userAgent:  "nasa-go/" + Version,
+		apodURL:    apodAPIURL,
+		epicURL:    epicAPIURL,
+		marsURL:    marsAPIURL,
+		mediaURL:   mediaAPIURL,
+		rateLimits: make(map[string]RateLimit),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithHTTPClient sets the *http.Client used to make requests.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		if h != nil {
+			c.httpClient = h
+		}
+	}
+}
+
+// WithAPIKey sets the API key used when the given params have none.
+// Without it, the key is read from the NASA_API_KEY environment variable.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithAPODURL sets the base URL of the APOD API.
+func WithAPODURL(u string) Option {
+	return func(c *Client) {
+		c.apodURL = strings.TrimRight(u, "/")
+	}
+}
+
+// WithEPICURL sets the base URL of the EPIC API.
+func WithEPICURL(u string) Option {
+	return func(c *Client) {
+		c.epicURL = strings.TrimRight(u, "/")
+	}
+}
+
+// WithKeylessEPICURLs makes EPIC build image URLs on the public
+// epic.gsfc.nasa.gov archive, which needs no API key, so the URLs are safe
+// to log or publish.
+func WithKeylessEPICURLs() Option {
+	return WithEPICArchiveURL(epicArchiveURL)
+}
+
+// WithEPICArchiveURL sets the base URL of a key-free EPIC archive; image URLs
+// are built on it without an api_key param.
+func WithEPICArchiveURL(u string) Option {
+	return func(c *Client) {
+		c.epicArchiveURL = strings.TrimRight(u, "/")
+	}
+}
+
+// WithMarsURL sets the base URL of the Mars Rover Photos API.
+func WithMarsURL(u string) Option {
+	return func(c *Client) {
+		c.marsURL = strings.TrimRight(u, "/")
+	}
+}
+
+// WithMediaURL sets the base URL of the NASA Image and Video Library API.
+func WithMediaURL(u string) Option {
+	return func(c *Client) {
+		c.mediaURL = strings.TrimRight(u, "/")
+	}
+}
+
+// withAPIKey returns p with the client API key filled in if p has none.
+func (c *Client) withAPIKey(p ParamEncoder) ParamEncoder {
+	k, ok := p.(apiKeyer)
+	if !ok || k.apiKey() != "" {
+		return p
+	}
+
+	key := c.APIKey()
+	if key == "" {
+		return p
+	}
+	return k.withAPIKey(key)
+}
+
+func (c *Client) getContent(ctx context.Context, e Endpoint, url string, p ParamEncoder) ([]byte, error) {
+	req, err := c.newRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if p != nil {
+		query, err := c.withAPIKey(p).Encode()
+		if err != nil {
+			return nil, err
+		}
+		req.URL.RawQuery = query
+	}
+
+	if c.cache != nil {
+		return c.doCached(req, e)
+	}
+
+	content, _, err := c.do(req)
+	return content, err
+}
+
+// newRequest returns a GET request for url carrying the client headers.
+func (c *Client) newRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return req, nil
+}
+
+// do sends req and reads the response body, retrying according to the
+// client RetryPolicy. The returned response body is already closed.
+func (c *Client) do(req *http.Request) ([]byte, *http.Response, error) {
+	var content []byte
+	resp, err := c.retry(req, func(resp *http.Response) error {
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		content = b
+		return err
+	})
+	return content, resp, err
+}
+
+// open sends req, retrying according to the client RetryPolicy, and returns
+// the response with its body unread. The caller must close the body.
+func (c *Client) open(req *http.Request) (*http.Response, error) {
+	return c.retry(req, nil)
+}
+
+// retry sends req until it succeeds or the client RetryPolicy gives up. If
+// read is set it is called with each successful response; an error from it
+// is retried like a transport error.
+func (c *Client) retry(req *http.Request, read func(*http.Response) error) (*http.Response, error) {
+	ctx := req.Context()
+	key := req.URL.Query().Get("api_key")
+
+	for attempt := 1; ; attempt++ {
+		if err := c.checkRateLimit(ctx, key); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.send(req, key)
+		if err == nil && read != nil {
+			if err = read(resp); err != nil {
+				resp = nil
+			}
+		}
+
+		a := RetryAttempt{
+			Attempt: attempt,
+			URL:     redactURL(req.URL),
+			Err:     err,
+		}
+		if resp != nil {
+			a.StatusCode = resp.StatusCode
+		}
+
+		retry := err != nil &&
+			attempt < c.retryPolicy.MaxAttempts &&
+			isIdempotent(req) &&
+			ctx.Err() == nil &&
+			c.retryPolicy.retryable(resp, err)
+		if retry {
+			var h http.Header
+			if resp != nil {
+				h = resp.Header
+			}
+			a.Wait = c.retryPolicy.backoff(attempt, h)
+		}
+
+		if c.retryPolicy.OnAttempt != nil {
+			c.retryPolicy.OnAttempt(a)
+		}
+
+		if !retry {
+			return resp, err
+		}
+
+		if err := sleep(ctx, a.Wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send makes a single attempt at req. On a 2xx or 304 response the body is
+// left open; on any other status it is read into an *APIError and closed.
+func (c *Client) send(req *http.Request, key string) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ue, ok := err.(*neturl.Error); ok {
+			ue.URL = RedactURL(ue.URL)
+		}
+		return nil, err
+	}
+
+	c.recordRateLimit(key, resp.Header)
+
+	if resp.StatusCode == http.StatusNotModified ||
+		resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	apiErr := newAPIError(req.URL, resp.StatusCode, content)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return resp, c.newRateLimitError(key, apiErr, resp.Header)
+	}
+	return resp, apiErr
+}
@@ -0,0 +1,141 @@
+package nasa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c := NewClient()
+
+		if c.httpClient != http.DefaultClient {
+			t.Error("expected http.DefaultClient")
+		}
+
+		if c.apodURL != apodAPIURL {
+			t.Errorf("expected: %s, got: %s", apodAPIURL, c.apodURL)
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		h := &http.Client{}
+		c := NewClient(
+			WithHTTPClient(h),
+			WithAPIKey("NASA_KEY"),
+			WithUserAgent("test-agent"),
+			WithAPODURL("http://localhost/apod/"),
+			WithEPICURL("http://localhost/epic"),
+			WithMarsURL("http://localhost/mars"),
+			WithMediaURL("http://localhost/media"),
+		)
+
+		if c.httpClient != h {
+			t.Error("custom http client not set")
+		}
+
+		if c.apiKey != "NASA_KEY" {
+			t.Errorf("expected: NASA_KEY, got: %s", c.apiKey)
+		}
+
+		if c.userAgent != "test-agent" {
+			t.Errorf("expected: test-agent, got: %s", c.userAgent)
+		}
+
+		if c.apodURL != "http://localhost/apod" {
+			t.Errorf("expected: http://localhost/apod, got: %s", c.apodURL)
+		}
+	})
+}
+
+func TestClientRequest(t *testing.T) {
+	var gotPath, gotQuery, gotAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+		gotAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"title":"Test APOD","date":"2020-04-29"}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(
+		WithAPODURL(ts.URL+"/apod"),
+		WithAPIKey("NASA_KEY"),
+		WithUserAgent("test-agent"),
+	)
+
+	t.Run("default API key", func(t *testing.T) {
+		img, err := c.APOD(&APODParams{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if img.Title != "Test APOD" {
+			t.Errorf("expected: Test APOD, got: %s", img.Title)
+		}
+
+		if gotPath != "/apod" {
+			t.Errorf("expected: /apod, got: %s", gotPath)
+		}
+
+		if gotQuery != "api_key=NASA_KEY" {
+			t.Errorf("expected: api_key=NASA_KEY, got: %s", gotQuery)
+		}
+
+		if gotAgent != "test-agent" {
+			t.Errorf("expected: test-agent, got: %s", gotAgent)
+		}
+	})
+
+	t.Run("params API key", func(t *testing.T) {
+		p := &APODParams{APIKey: "OTHER_KEY"}
+		_, err := c.APOD(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if gotQuery != "api_key=OTHER_KEY" {
+			t.Errorf("expected: api_key=OTHER_KEY, got: %s", gotQuery)
+		}
+	})
+
+	t.Run("params not modified", func(t *testing.T) {
+		p := &APODParams{}
+		_, err := c.APOD(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p.APIKey != "" {
+			t.Errorf("params API key was modified: %s", p.APIKey)
+		}
+	})
+}
+
+func TestClientContext(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"location":"http://` + r.Host + `/metadata.json"}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithMediaURL(ts.URL))
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := c.GetMediaMetadataContext(ctx, "PIA12345")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got: %v", err)
+		}
+
+		if requests != 0 {
+			t.Errorf("expected no requests, got: %d", requests)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+// Command nasa-mirror keeps a local mirror of APOD, EPIC and Mars rover
+// photo data, as configured by a JSON file (see package mirror).
+//
+//	nasa-mirror -config mirror.json
+//	nasa-mirror -config mirror.json -verify
+//
+// The API key is read from the NASA_API_KEY environment variable.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/Oshuma/nasa"
+	"github.com/Oshuma/nasa/mirror"
+)
+
+func main() {
+	config := flag.String("config", "mirror.json", "path to the mirror config")
+	verify := flag.Bool("verify", false, "verify mirrored files against their checksums")
+	flag.Parse()
+
+	cfg, err := mirror.LoadConfig(*config)
+	if err != nil {
+		fatal(err)
+	}
+
+	c := nasa.NewClient(
+		nasa.WithRetryPolicy(nasa.DefaultRetryPolicy),
+		nasa.WithRateLimitPolicy(nasa.RateLimitWait),
+	)
+
+	m, err := mirror.New(c, cfg)
+	if err != nil {
+		fatal(err)
+	}
+
+	if *verify {
+		bad, err := m.Verify()
+		if err != nil {
+			fatal(err)
+		}
+		for _, rel := range bad {
+			fmt.Println("bad:", rel)
+		}
+		fmt.Printf("%d files verified, %d bad; run again without -verify to fetch them\n",
+			len(m.Manifest().Files)+len(bad), len(bad))
+		if len(bad) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		<-sig
+		cancel()
+	}()
+
+	stats, err := m.Sync(ctx)
+	fmt.Printf("%d written, %d unchanged, %d skipped\n", stats.Written, stats.Unchanged, stats.Skipped)
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "nasa-mirror:", err)
+	os.Exit(1)
+}
@@ -20,6 +20,11 @@ func (d *Date) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON marshals the date formatted as YYYY-MM-DD.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.Format("2006-01-02") + `"`), nil
+}
+
 // EPICDate is a time.Time wrapper used to parse the EPIC date response.
 type EPICDate struct {
 	time.Time
@@ -35,6 +40,11 @@ func (d *EPICDate) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON marshals the date formatted as YYYY-MM-DD HH:MM:SS.
+func (d EPICDate) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.Format("2006-01-02 15:04:05") + `"`), nil
+}
+
 func parseTime(b []byte, format string) (time.Time, error) {
 	s := strings.Trim(string(b), "\"")
 	t, err := time.Parse(format, s)
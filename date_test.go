@@ -43,3 +43,31 @@ func TestUnmarshalJSON(t *testing.T) {
 		}
 	})
 }
+
+func TestMarshalJSON(t *testing.T) {
+	t.Run("Date", func(t *testing.T) {
+		d := Date{Time: time.Date(2020, 4, 29, 0, 0, 0, 0, time.UTC)}
+		out, err := d.MarshalJSON()
+		if err != nil {
+			t.Error(err)
+		}
+
+		expected := `"2020-04-29"`
+		if string(out) != expected {
+			t.Errorf("expected: %s, got: %s", expected, out)
+		}
+	})
+
+	t.Run("EPICDate", func(t *testing.T) {
+		d := EPICDate{Time: time.Date(2020, 4, 29, 9, 8, 7, 0, time.UTC)}
+		out, err := d.MarshalJSON()
+		if err != nil {
+			t.Error(err)
+		}
+
+		expected := `"2020-04-29 09:08:07"`
+		if string(out) != expected {
+			t.Errorf("expected: %s, got: %s", expected, out)
+		}
+	})
+}
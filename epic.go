@@ -1,16 +1,65 @@
 package nasa
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
 	"strings"
 )
 
 const (
-	epicAPIURL         = "https://api.nasa.gov/EPIC"
-	epicImageURLFormat = "%s/archive/%s/%s/%s/%s/%s/%s.%s?api_key=%s"
+	epicImageURLFormat = "%s/archive/%s/%s/%s/%s/%s/%s.%s"
+
+	// epicArchiveURL serves the EPIC archive without needing an API key.
+	epicArchiveURL = "https://epic.gsfc.nasa.gov"
+)
+
+// EPICCollection is a collection of EPIC imagery.
+type EPICCollection string
+
+// The EPIC collections.
+const (
+	EPICCollectionNatural  EPICCollection = "natural"
+	EPICCollectionEnhanced EPICCollection = "enhanced"
+	EPICCollectionAerosol  EPICCollection = "aerosol"
+	EPICCollectionCloud    EPICCollection = "cloud"
 )
 
+// EPICCollections lists every EPIC collection.
+var EPICCollections = []EPICCollection{
+	EPICCollectionNatural,
+	EPICCollectionEnhanced,
+	EPICCollectionAerosol,
+	EPICCollectionCloud,
+}
+
+// epicNameTokens are the tokens identifying the collection in image names,
+// such as epic_RGB_20200424002712.
+var epicNameTokens = map[EPICCollection]string{
+	EPICCollectionNatural:  "1b",
+	EPICCollectionEnhanced: "RGB",
+	EPICCollectionAerosol:  "uvai",
+	EPICCollectionCloud:    "cloudfraction",
+}
+
+// Valid reports whether c is one of EPICCollections.
+func (c EPICCollection) Valid() bool {
+	_, ok := epicNameTokens[c]
+	return ok
+}
+
+// epicImageName returns the name of the image in collection c taken at the
+// same time as the image called name.
+func epicImageName(name string, c EPICCollection) string {
+	i := strings.LastIndex(name, "_")
+	if !strings.HasPrefix(name, "epic_") || i < len("epic_") {
+		return name
+	}
+	return "epic_" + epicNameTokens[c] + name[i:]
+}
+
 // EPICImage represents an image from the Earth Polychromatic Imaging Camera.
 type EPICImage struct {
 	Date       EPICDate `json:"date"`
@@ -18,37 +67,75 @@ type EPICImage struct {
 	Caption    string   `json:"caption"`
 	Image      string   `json:"image"`
 	Version    string   `json:"version"`
-	Coords     struct {
+
+	// Collection is the collection the image was requested from.
+	Collection EPICCollection `json:"collection,omitempty"`
+
+	Coords struct {
 		Centroid LatLon      `json:"centroid_coordinates"`
 		Dscovr   XYZ         `json:"dscovr_j2000_position"`
 		Lunar    XYZ         `json:"lunar_j2000_position"`
 		Sun      XYZ         `json:"sun_j2000_position"`
 		Attitude Quaternions `json:"attitude_quaternions"`
 	} `json:"coords"`
+	// URL holds the archive URLs of the image. Image is the image in its own
+	// collection. Natural and Enhanced are set for images of either of those
+	// collections, which are made from the same exposures.
 	URL struct {
+		Image    string
 		Natural  string
 		Enhanced string
 		Thumb    struct {
+			Image    string
 			Natural  string
 			Enhanced string
 		} `json:"-"`
 	} `json:"-"`
 }
 
+// collection returns the collection of the image, falling back to the one
+// named in the image name.
+func (e *EPICImage) collection() EPICCollection {
+	if e.Collection != "" {
+		return e.Collection
+	}
+	for c, token := range epicNameTokens {
+		if strings.HasPrefix(e.Image, "epic_"+token+"_") {
+			return c
+		}
+	}
+	return EPICCollectionNatural
+}
+
 // EPIC gets a response from the Earth Polychromatic Imaging Camera.
 func EPIC(p ParamEncoder) (EPICImages, error) {
-	params, ok := p.(*EPICParams)
-	if !ok {
+	return DefaultClient.EPIC(p)
+}
+
+// EPICContext is like EPIC but uses the given context.
+func EPICContext(ctx context.Context, p ParamEncoder) (EPICImages, error) {
+	return DefaultClient.EPICContext(ctx, p)
+}
+
+// EPIC gets a response from the Earth Polychromatic Imaging Camera.
+func (c *Client) EPIC(p ParamEncoder) (EPICImages, error) {
+	return c.EPICContext(context.Background(), p)
+}
+
+// EPICContext is like EPIC but uses the given context.
+func (c *Client) EPICContext(ctx context.Context, p ParamEncoder) (EPICImages, error) {
+	if _, ok := p.(*EPICParams); !ok {
 		return EPICImages{}, ErrorParamsMismatch
 	}
+	params := c.withAPIKey(p).(*EPICParams)
 
-	query, err := p.Encode()
+	query, err := params.Encode()
 	if err != nil {
 		return EPICImages{}, err
 	}
 
-	url := fmt.Sprintf("%s/%s", epicAPIURL, query)
-	content, err := getContent(url, nil)
+	url := fmt.Sprintf("%s/%s", c.epicURL, query)
+	content, err := c.getContent(ctx, EndpointEPIC, url, nil)
 	if err != nil {
 		return EPICImages{}, err
 	}
@@ -59,7 +146,15 @@ func EPIC(p ParamEncoder) (EPICImages, error) {
 		return EPICImages{}, err
 	}
 
-	images.buildURLs(params)
+	for _, img := range images {
+		img.Collection = params.collection()
+	}
+
+	if c.epicArchiveURL != "" {
+		images.buildURLs(c.epicArchiveURL, "")
+	} else {
+		images.buildURLs(c.epicURL, params.APIKey)
+	}
 
 	return images, nil
 }
@@ -67,67 +162,158 @@ func EPIC(p ParamEncoder) (EPICImages, error) {
 // EPICImages is an array of pointers to EPICImage.
 type EPICImages []*EPICImage
 
-func (images EPICImages) buildURLs(p *EPICParams) {
+// buildURLs builds the archive URLs of every image. If key is empty the URLs
+// carry no api_key param.
+func (images EPICImages) buildURLs(base, key string) {
 	for _, epic := range images {
-		epic.buildNaturalURLs(p)
-		epic.buildEnhancedURLs(p)
+		epic.buildURLs(base, key)
 	}
 }
 
-// Full:  https://api.nasa.gov/EPIC/archive/natural/2020/04/24/png/epic_1b_20200424002712.png?api_key=DEMO_KEY
-// Thumb: https://api.nasa.gov/EPIC/archive/natural/2020/04/24/thumbs/epic_1b_20200424002712.jpg?api_key=DEMO_KEY
-func (e *EPICImage) buildNaturalURLs(p *EPICParams) {
-	e.URL.Natural = fmt.Sprintf(epicImageURLFormat,
-		epicAPIURL,
-		"natural",
+// epicImageURL formats an archive image URL, adding the key if one is given.
+func epicImageURL(base, collection string, e *EPICImage, dir, name, ext, key string) string {
+	u := fmt.Sprintf(epicImageURLFormat,
+		base,
+		collection,
 		e.Date.Format("2006"), // Year
 		e.Date.Format("01"),   // Month
 		e.Date.Format("02"),   // Day
-		"png",
-		e.Image,
-		"png",
-		p.APIKey,
+		dir,
+		name,
+		ext,
 	)
 
-	e.URL.Thumb.Natural = fmt.Sprintf(epicImageURLFormat,
-		epicAPIURL,
-		"natural",
-		e.Date.Format("2006"), // Year
-		e.Date.Format("01"),   // Month
-		e.Date.Format("02"),   // Day
-		"thumbs",
-		e.Image,
-		"jpg",
-		p.APIKey,
-	)
+	if key != "" {
+		u += "?api_key=" + url.QueryEscape(key)
+	}
+
+	return u
+}
+
+// Full:  https://api.nasa.gov/EPIC/archive/natural/2020/04/24/png/epic_1b_20200424002712.png?api_key=DEMO_KEY
+// Thumb: https://api.nasa.gov/EPIC/archive/natural/2020/04/24/thumbs/epic_1b_20200424002712.jpg?api_key=DEMO_KEY
+func (e *EPICImage) buildNaturalURLs(base, key string) {
+	name := epicImageName(e.Image, EPICCollectionNatural)
+
+	e.URL.Natural = epicImageURL(base, "natural", e, "png", name, "png", key)
+	e.URL.Thumb.Natural = epicImageURL(base, "natural", e, "thumbs", name, "jpg", key)
 }
 
 // Full:  https://api.nasa.gov/EPIC/archive/enhanced/2020/04/24/png/epic_RGB_20200424002712.png?api_key=DEMO_KEY
 // Thumb: https://api.nasa.gov/EPIC/archive/enhanced/2020/04/24/thumbs/epic_RGB_20200424002712.jpg?api_key=DEMO_KEY
-func (e *EPICImage) buildEnhancedURLs(p *EPICParams) {
-	enhancedID := strings.Replace(e.Image, "_1b_", "_RGB_", 1)
+func (e *EPICImage) buildEnhancedURLs(base, key string) {
+	name := epicImageName(e.Image, EPICCollectionEnhanced)
 
-	e.URL.Enhanced = fmt.Sprintf(epicImageURLFormat,
-		epicAPIURL,
-		"enhanced",
-		e.Date.Format("2006"), // Year
-		e.Date.Format("01"),   // Month
-		e.Date.Format("02"),   // Day
-		"png",
-		enhancedID,
-		"png",
-		p.APIKey,
-	)
+	e.URL.Enhanced = epicImageURL(base, "enhanced", e, "png", name, "png", key)
+	e.URL.Thumb.Enhanced = epicImageURL(base, "enhanced", e, "thumbs", name, "jpg", key)
+}
 
-	e.URL.Thumb.Enhanced = fmt.Sprintf(epicImageURLFormat,
-		epicAPIURL,
-		"enhanced",
-		e.Date.Format("2006"), // Year
-		e.Date.Format("01"),   // Month
-		e.Date.Format("02"),   // Day
-		"thumbs",
-		enhancedID,
-		"jpg",
-		p.APIKey,
-	)
+// EPICImageVariant selects one of the archive images of an EPICImage.
+type EPICImageVariant int
+
+// The archive images available for an EPICImage. The natural and enhanced
+// variants are the images of those collections taken at the same time; the
+// EPICImage variants are in the collection of the image itself.
+const (
+	EPICNatural EPICImageVariant = iota
+	EPICEnhanced
+	EPICNaturalThumb
+	EPICEnhancedThumb
+	EPICImagePNG
+	EPICImageJPG
+	EPICImageThumb
+)
+
+// ArchivePath returns the slash separated path of the variant image within
+// the EPIC archive, such as natural/2020/04/24/png/epic_1b_20200424002712.png.
+func (e *EPICImage) ArchivePath(v EPICImageVariant) (string, error) {
+	u, err := e.archiveURL("", "", v)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(u, "/archive/"), nil
+}
+
+// archiveURL returns the URL of the variant image using the given base and key.
+func (e *EPICImage) archiveURL(base, key string, v EPICImageVariant) (string, error) {
+	collection, dir, ext := e.collection(), "png", "png"
+
+	switch v {
+	case EPICNatural:
+		collection = EPICCollectionNatural
+	case EPICEnhanced:
+		collection = EPICCollectionEnhanced
+	case EPICNaturalThumb:
+		collection, dir, ext = EPICCollectionNatural, "thumbs", "jpg"
+	case EPICEnhancedThumb:
+		collection, dir, ext = EPICCollectionEnhanced, "thumbs", "jpg"
+	case EPICImagePNG:
+	case EPICImageJPG:
+		dir, ext = "jpg", "jpg"
+	case EPICImageThumb:
+		dir, ext = "thumbs", "jpg"
+	default:
+		return "", fmt.Errorf("unknown EPIC image variant %d", v)
+	}
+
+	name := epicImageName(e.Image, collection)
+	return epicImageURL(base, string(collection), e, dir, name, ext, key), nil
+}
+
+func (e *EPICImage) buildURLs(base, key string) {
+	collection := e.collection()
+	e.URL.Image = epicImageURL(base, string(collection), e, "png", e.Image, "png", key)
+	e.URL.Thumb.Image = epicImageURL(base, string(collection), e, "thumbs", e.Image, "jpg", key)
+
+	if collection == EPICCollectionNatural || collection == EPICCollectionEnhanced {
+		e.buildNaturalURLs(base, key)
+		e.buildEnhancedURLs(base, key)
+	}
+}
+
+// DownloadEPICImage writes the variant image of img to w.
+func DownloadEPICImage(img *EPICImage, v EPICImageVariant, w io.Writer) (int64, error) {
+	return DefaultClient.DownloadEPICImage(img, v, w)
+}
+
+// DownloadEPICImageContext is like DownloadEPICImage but uses the given context.
+func DownloadEPICImageContext(ctx context.Context, img *EPICImage, v EPICImageVariant, w io.Writer) (int64, error) {
+	return DefaultClient.DownloadEPICImageContext(ctx, img, v, w)
+}
+
+// DownloadEPICImage writes the variant image of img to w. The API key is
+// resolved when the request is made rather than taken from img.URL, so
+// images built with key-free URLs can still be downloaded through api.nasa.gov.
+func (c *Client) DownloadEPICImage(img *EPICImage, v EPICImageVariant, w io.Writer) (int64, error) {
+	return c.DownloadEPICImageContext(context.Background(), img, v, w)
+}
+
+// DownloadEPICImageContext is like DownloadEPICImage but uses the given context.
+func (c *Client) DownloadEPICImageContext(ctx context.Context, img *EPICImage, v EPICImageVariant, w io.Writer) (int64, error) {
+	base, key := c.epicArchive()
+	u, err := img.archiveURL(base, key, v)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := c.newRequest(ctx, u)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.open(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return io.Copy(w, resp.Body)
+}
+
+// epicArchive returns the base URL and key to build archive URLs with.
+func (c *Client) epicArchive() (string, string) {
+	if c.epicArchiveURL != "" {
+		return c.epicArchiveURL, ""
+	}
+	return c.epicURL, c.APIKey()
 }
@@ -0,0 +1,157 @@
+package nasa
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// EPICImageSize is the width and height of full EPIC images in pixels.
+	EPICImageSize = 2048
+
+	// dscovrL1Distance is a typical distance from Earth to DSCOVR in km,
+	// used for images without a DSCOVR position.
+	dscovrL1Distance = 1.5e6
+)
+
+// EPICCoverage describes where a coordinate appears in an EPIC image.
+type EPICCoverage struct {
+	Image *EPICImage
+
+	// Distance is the angle in degrees between the coordinate and the image
+	// centroid, the point of Earth right below DSCOVR, seen from the center
+	// of Earth.
+	Distance float64
+
+	// OnDisk reports whether the coordinate is on the side of Earth facing
+	// DSCOVR, and Sunlit whether it is on the day side.
+	OnDisk bool
+	Sunlit bool
+
+	// X and Y are the approximate pixel position of the coordinate in the
+	// EPICImageSize by EPICImageSize image, from the top left corner. They
+	// assume Earth is centered with north up, as in the archive images, and
+	// are only meaningful when OnDisk is true.
+	X, Y float64
+}
+
+// Visible reports whether the coordinate is on the visible sunlit disk.
+func (c EPICCoverage) Visible() bool {
+	return c.OnDisk && c.Sunlit
+}
+
+// Locate returns where target appears in the image.
+func (e *EPICImage) Locate(target LatLon) EPICCoverage {
+	centroid := e.Coords.Centroid
+	cov := EPICCoverage{
+		Image:    e,
+		Distance: centralAngle(centroid, target),
+	}
+
+	lat0 := radians(centroid.Lat)
+	lat, dlon := radians(target.Lat), radians(target.Lon-centroid.Lon)
+
+	// The coordinate on the unit sphere, with z towards DSCOVR, x east and
+	// y north on the image.
+	x := math.Cos(lat) * math.Sin(dlon)
+	y := math.Cos(lat0)*math.Sin(lat) - math.Sin(lat0)*math.Cos(lat)*math.Cos(dlon)
+	z := math.Sin(lat0)*math.Sin(lat) + math.Cos(lat0)*math.Cos(lat)*math.Cos(dlon)
+
+	d := e.DscovrDistance()
+	if d == 0 {
+		d = dscovrL1Distance
+	}
+	d /= earthRadius
+
+	// From a finite distance less than a hemisphere is in view.
+	cov.OnDisk = z > 1/d
+
+	pixels := EPICImageSize / EPICFieldOfView
+	cov.X = EPICImageSize/2 + degrees(math.Atan2(x, d-z))*pixels
+	cov.Y = EPICImageSize/2 - degrees(math.Atan2(y, d-z))*pixels
+
+	cov.Sunlit = centralAngle(subsolarPoint(e.Date.Time), target) < 90
+
+	return cov
+}
+
+// Covering returns the coverage of target in every image, closest to the
+// centroid first.
+func (images EPICImages) Covering(target LatLon) []EPICCoverage {
+	covs := make([]EPICCoverage, len(images))
+	for i, img := range images {
+		covs[i] = img.Locate(target)
+	}
+
+	sort.SliceStable(covs, func(i, j int) bool { return covs[i].Distance < covs[j].Distance })
+	return covs
+}
+
+// EPICCovering gets the EPIC images selected by p and ranks them by how
+// close target is to their centroid.
+func EPICCovering(p ParamEncoder, target LatLon) ([]EPICCoverage, error) {
+	return DefaultClient.EPICCovering(p, target)
+}
+
+// EPICCoveringContext is like EPICCovering but uses the given context.
+func EPICCoveringContext(ctx context.Context, p ParamEncoder, target LatLon) ([]EPICCoverage, error) {
+	return DefaultClient.EPICCoveringContext(ctx, p, target)
+}
+
+// EPICCovering gets the EPIC images selected by p and ranks them by how
+// close target is to their centroid.
+func (c *Client) EPICCovering(p ParamEncoder, target LatLon) ([]EPICCoverage, error) {
+	return c.EPICCoveringContext(context.Background(), p, target)
+}
+
+// EPICCoveringContext is like EPICCovering but uses the given context.
+func (c *Client) EPICCoveringContext(ctx context.Context, p ParamEncoder, target LatLon) ([]EPICCoverage, error) {
+	images, err := c.EPICContext(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return images.Covering(target), nil
+}
+
+// centralAngle returns the angle in degrees between a and b seen from the
+// center of Earth.
+func centralAngle(a, b LatLon) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dlat, dlon := lat2-lat1, radians(b.Lon-a.Lon)
+
+	h := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	return degrees(2 * math.Asin(math.Min(1, math.Sqrt(h))))
+}
+
+// subsolarPoint returns the point of Earth with the Sun at its zenith at t,
+// using the low precision solar coordinates of the Astronomical Almanac,
+// good to about 0.01 degrees.
+func subsolarPoint(t time.Time) LatLon {
+	n := t.Sub(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)).Hours() / 24
+
+	l := 280.460 + 0.9856474*n
+	g := radians(357.528 + 0.9856003*n)
+	lambda := radians(l + 1.915*math.Sin(g) + 0.020*math.Sin(2*g))
+	epsilon := radians(23.439 - 0.0000004*n)
+
+	ra := degrees(math.Atan2(math.Cos(epsilon)*math.Sin(lambda), math.Cos(lambda)))
+	dec := degrees(math.Asin(math.Sin(epsilon) * math.Sin(lambda)))
+	gmst := 280.46061837 + 360.98564736629*n
+
+	return LatLon{Lat: dec, Lon: normalizeLon(ra - gmst)}
+}
+
+// normalizeLon returns lon in the range [-180, 180).
+func normalizeLon(lon float64) float64 {
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
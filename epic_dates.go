@@ -0,0 +1,155 @@
+package nasa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+)
+
+const (
+	epicAllPath       = "%s/api/%s/all"
+	epicAvailablePath = "%s/api/%s/available"
+)
+
+// EPICAvailableDates returns every date with imagery in the collection of p,
+// an *EPICParams whose Date is ignored, oldest first.
+func EPICAvailableDates(p ParamEncoder) ([]time.Time, error) {
+	return DefaultClient.EPICAvailableDates(p)
+}
+
+// EPICAvailableDatesContext is like EPICAvailableDates but uses the given context.
+func EPICAvailableDatesContext(ctx context.Context, p ParamEncoder) ([]time.Time, error) {
+	return DefaultClient.EPICAvailableDatesContext(ctx, p)
+}
+
+// EPICAvailableDates returns every date with imagery in the collection of p,
+// an *EPICParams whose Date is ignored, oldest first.
+func (c *Client) EPICAvailableDates(p ParamEncoder) ([]time.Time, error) {
+	return c.EPICAvailableDatesContext(context.Background(), p)
+}
+
+// EPICAvailableDatesContext is like EPICAvailableDates but uses the given context.
+func (c *Client) EPICAvailableDatesContext(ctx context.Context, p ParamEncoder) ([]time.Time, error) {
+	content, err := c.epicDates(ctx, epicAvailablePath, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// ["2015-06-13", ...]
+	days := []string{}
+	if err := json.Unmarshal(content, &days); err != nil {
+		return nil, err
+	}
+
+	return parseEPICDates(days)
+}
+
+// EPICAllDates returns every date with imagery in the collection of p, an
+// *EPICParams whose Date is ignored, oldest first. It uses the "all"
+// endpoint, which lists the same dates as the "available" one in a different
+// format.
+func EPICAllDates(p ParamEncoder) ([]time.Time, error) {
+	return DefaultClient.EPICAllDates(p)
+}
+
+// EPICAllDatesContext is like EPICAllDates but uses the given context.
+func EPICAllDatesContext(ctx context.Context, p ParamEncoder) ([]time.Time, error) {
+	return DefaultClient.EPICAllDatesContext(ctx, p)
+}
+
+// EPICAllDates returns every date with imagery in the collection of p, an
+// *EPICParams whose Date is ignored, oldest first. It uses the "all"
+// endpoint, which lists the same dates as the "available" one in a different
+// format.
+func (c *Client) EPICAllDates(p ParamEncoder) ([]time.Time, error) {
+	return c.EPICAllDatesContext(context.Background(), p)
+}
+
+// EPICAllDatesContext is like EPICAllDates but uses the given context.
+func (c *Client) EPICAllDatesContext(ctx context.Context, p ParamEncoder) ([]time.Time, error) {
+	content, err := c.epicDates(ctx, epicAllPath, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// [{"date": "2015-06-13"}, ...]
+	entries := []struct {
+		Date string `json:"date"`
+	}{}
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, err
+	}
+
+	days := make([]string, len(entries))
+	for i, e := range entries {
+		days[i] = e.Date
+	}
+
+	return parseEPICDates(days)
+}
+
+func (c *Client) epicDates(ctx context.Context, path string, p ParamEncoder) ([]byte, error) {
+	if _, ok := p.(*EPICParams); !ok {
+		return nil, ErrorParamsMismatch
+	}
+	params := c.withAPIKey(p).(*EPICParams)
+
+	if params.APIKey == "" {
+		return nil, ErrorNoAPIKey
+	}
+
+	collection := params.collection()
+	if !collection.Valid() {
+		return nil, ErrorUnknownEPICCollection
+	}
+
+	query := url.Values{"api_key": {params.APIKey}}
+	u := fmt.Sprintf(path, c.epicURL, collection) + "?" + query.Encode()
+	return c.getContent(ctx, EndpointEPIC, u, nil)
+}
+
+// parseEPICDates parses days as UTC dates and sorts them, oldest first.
+func parseEPICDates(days []string) ([]time.Time, error) {
+	dates := make([]time.Time, 0, len(days))
+	for _, d := range days {
+		// Some listings carry a time as well; only the day matters.
+		if len(d) > len("2006-01-02") {
+			d = d[:len("2006-01-02")]
+		}
+
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			return nil, err
+		}
+		dates = append(dates, t)
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, nil
+}
+
+// NearestEPICDate returns the date in dates closest to date, preferring the
+// earlier one on a tie. Dates must be sorted oldest first, as returned by
+// EPICAvailableDates. It returns false if dates is empty.
+func NearestEPICDate(dates []time.Time, date time.Time) (time.Time, bool) {
+	if len(dates) == 0 {
+		return time.Time{}, false
+	}
+
+	i := sort.Search(len(dates), func(i int) bool { return !dates[i].Before(date) })
+	switch {
+	case i == 0:
+		return dates[0], true
+	case i == len(dates):
+		return dates[i-1], true
+	}
+
+	before, after := dates[i-1], dates[i]
+	if after.Sub(date) < date.Sub(before) {
+		return after, true
+	}
+	return before, true
+}
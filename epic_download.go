@@ -0,0 +1,191 @@
+package nasa
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultEPICDownloadConcurrency is the default EPICDownloadOptions.Concurrency.
+const DefaultEPICDownloadConcurrency = 4
+
+// EPICDownloadOptions configures DownloadEPICImages.
+type EPICDownloadOptions struct {
+	// Variants are the formats to download: EPICImagePNG, EPICImageJPG or
+	// EPICImageThumb. It defaults to EPICImagePNG.
+	Variants []EPICImageVariant
+
+	// Collections are the collections to download every image from. It
+	// defaults to the collection of each image. Images taken at the same
+	// time share their names but for the collection token; ones missing from
+	// a collection fail with ErrorNotFound.
+	Collections []EPICCollection
+
+	// Concurrency is how many files are downloaded at once. It defaults to
+	// DefaultEPICDownloadConcurrency.
+	Concurrency int
+}
+
+// EPICDownload is the result of downloading one file of an EPIC image.
+type EPICDownload struct {
+	Image      *EPICImage
+	Collection EPICCollection
+	Variant    EPICImageVariant
+
+	// Path is the file the image was written to.
+	Path string
+
+	// Size is the size of the file in bytes.
+	Size int64
+
+	// Skipped reports whether the file already existed and was not downloaded.
+	Skipped bool
+
+	// Err is the error downloading the file, if any.
+	Err error
+}
+
+// DownloadEPICImages downloads images into dir.
+func DownloadEPICImages(images EPICImages, dir string, opts *EPICDownloadOptions) ([]EPICDownload, error) {
+	return DefaultClient.DownloadEPICImages(images, dir, opts)
+}
+
+// DownloadEPICImagesContext is like DownloadEPICImages but uses the given context.
+func DownloadEPICImagesContext(ctx context.Context, images EPICImages, dir string, opts *EPICDownloadOptions) ([]EPICDownload, error) {
+	return DefaultClient.DownloadEPICImagesContext(ctx, images, dir, opts)
+}
+
+// DownloadEPICImages downloads the selected variants and collections of
+// images into dir, laid out like the EPIC archive:
+//
+//	natural/2020/04/24/png/epic_1b_20200424002712.png
+//	enhanced/2020/04/24/thumbs/epic_RGB_20200424002712.jpg
+//
+// Existing files are skipped. Files are written with a ".part" suffix and
+// renamed once complete, so an existing file is always whole.
+//
+// A result is returned for every file, in order of the images, then
+// collections, then variants. The returned error is that of the first failed
+// file, or an error with the options.
+func (c *Client) DownloadEPICImages(images EPICImages, dir string, opts *EPICDownloadOptions) ([]EPICDownload, error) {
+	return c.DownloadEPICImagesContext(context.Background(), images, dir, opts)
+}
+
+// DownloadEPICImagesContext is like DownloadEPICImages but uses the given context.
+func (c *Client) DownloadEPICImagesContext(ctx context.Context, images EPICImages, dir string, opts *EPICDownloadOptions) ([]EPICDownload, error) {
+	if opts == nil {
+		opts = &EPICDownloadOptions{}
+	}
+
+	variants := opts.Variants
+	if len(variants) == 0 {
+		variants = []EPICImageVariant{EPICImagePNG}
+	}
+	for _, v := range variants {
+		if v != EPICImagePNG && v != EPICImageJPG && v != EPICImageThumb {
+			return nil, fmt.Errorf("EPIC image variant %d cannot be batch downloaded", v)
+		}
+	}
+
+	for _, collection := range opts.Collections {
+		if !collection.Valid() {
+			return nil, ErrorUnknownEPICCollection
+		}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultEPICDownloadConcurrency
+	}
+
+	results := []EPICDownload{}
+	for _, img := range images {
+		collections := opts.Collections
+		if len(collections) == 0 {
+			collections = []EPICCollection{img.collection()}
+		}
+
+		for _, collection := range collections {
+			for _, v := range variants {
+				results = append(results, EPICDownload{Image: img, Collection: collection, Variant: v})
+			}
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < concurrency && n < len(results); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				c.downloadEPICFile(ctx, dir, &results[i])
+			}
+		}()
+	}
+
+	for i := range results {
+		if ctx.Err() != nil {
+			results[i].Err = ctx.Err()
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Err != nil {
+			return results, r.Err
+		}
+	}
+	return results, nil
+}
+
+// downloadEPICFile downloads the file of r into dir, recording the outcome in r.
+func (c *Client) downloadEPICFile(ctx context.Context, dir string, r *EPICDownload) {
+	// The image in the requested collection, taken at the same time.
+	img := *r.Image
+	img.Collection = r.Collection
+	img.Image = epicImageName(r.Image.Image, r.Collection)
+
+	rel, err := img.ArchivePath(r.Variant)
+	if err != nil {
+		r.Err = err
+		return
+	}
+	r.Path = filepath.Join(dir, filepath.FromSlash(rel))
+
+	if fi, err := os.Stat(r.Path); err == nil {
+		r.Size = fi.Size()
+		r.Skipped = true
+		return
+	}
+
+	r.Size, r.Err = c.writeEPICFile(ctx, &img, r.Variant, r.Path)
+}
+
+func (c *Client) writeEPICFile(ctx context.Context, img *EPICImage, v EPICImageVariant, path string) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, err
+	}
+
+	part := path + ".part"
+	f, err := os.Create(part)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := c.DownloadEPICImageContext(ctx, img, v, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(part)
+		return 0, err
+	}
+
+	return n, os.Rename(part, path)
+}
@@ -0,0 +1,108 @@
+package nasa
+
+import "math"
+
+const (
+	// EPICFieldOfView is the field of view of the EPIC camera, in degrees.
+	EPICFieldOfView = 0.61
+
+	// Radii in km: the equatorial radius of Earth (WGS 84) and the mean
+	// radius of the Moon.
+	earthRadius = 6378.137
+	moonRadius  = 1737.4
+)
+
+// The J2000 positions of EPICImage.Coords are in km, relative to the center
+// of Earth.
+
+// DscovrDistance returns the distance from Earth to DSCOVR in km.
+func (e *EPICImage) DscovrDistance() float64 {
+	return e.Coords.Dscovr.norm()
+}
+
+// MoonDistance returns the distance from Earth to the Moon in km.
+func (e *EPICImage) MoonDistance() float64 {
+	return e.Coords.Lunar.norm()
+}
+
+// SEVAngle returns the Sun-Earth-Vehicle angle in degrees: the angle at
+// Earth between the Sun and DSCOVR. It is the offset from which EPIC sees
+// the sunlit side of Earth.
+func (e *EPICImage) SEVAngle() float64 {
+	return angle(e.Coords.Sun, e.Coords.Dscovr)
+}
+
+// EarthAngularSize returns the apparent diameter of Earth seen from DSCOVR,
+// in degrees.
+func (e *EPICImage) EarthAngularSize() float64 {
+	return angularSize(earthRadius, e.DscovrDistance())
+}
+
+// MoonInFrame reports whether any part of the Moon lies within the EPIC
+// field of view, taken as a circle around Earth. The Moon may still be
+// hidden behind Earth.
+func (e *EPICImage) MoonInFrame() bool {
+	toEarth := e.Coords.Dscovr.scale(-1)
+	toMoon := e.Coords.Lunar.sub(e.Coords.Dscovr)
+
+	d := toMoon.norm()
+	if d == 0 {
+		return false
+	}
+
+	return angle(toEarth, toMoon)-angularSize(moonRadius, d)/2 < EPICFieldOfView/2
+}
+
+// RotationMatrix returns the rotation matrix of the attitude quaternion,
+// with Q0 as the scalar part. The quaternion is normalized first; the zero
+// quaternion yields the identity matrix.
+func (q Quaternions) RotationMatrix() [3][3]float64 {
+	n := math.Sqrt(q.Q0*q.Q0 + q.Q1*q.Q1 + q.Q2*q.Q2 + q.Q3*q.Q3)
+	if n == 0 {
+		return [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	}
+	w, x, y, z := q.Q0/n, q.Q1/n, q.Q2/n, q.Q3/n
+
+	return [3][3]float64{
+		{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y)},
+		{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x)},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y)},
+	}
+}
+
+func (v XYZ) norm() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+func (v XYZ) dot(u XYZ) float64 {
+	return v.X*u.X + v.Y*u.Y + v.Z*u.Z
+}
+
+func (v XYZ) sub(u XYZ) XYZ {
+	return XYZ{X: v.X - u.X, Y: v.Y - u.Y, Z: v.Z - u.Z}
+}
+
+func (v XYZ) scale(f float64) XYZ {
+	return XYZ{X: v.X * f, Y: v.Y * f, Z: v.Z * f}
+}
+
+// angle returns the angle between v and u in degrees.
+func angle(v, u XYZ) float64 {
+	n := v.norm() * u.norm()
+	if n == 0 {
+		return 0
+	}
+
+	// Rounding can push the cosine just past ±1.
+	cos := math.Max(-1, math.Min(1, v.dot(u)/n))
+	return degrees(math.Acos(cos))
+}
+
+// angularSize returns the apparent diameter in degrees of a sphere of
+// radius r seen from distance d.
+func angularSize(r, d float64) float64 {
+	if d <= r {
+		return 180
+	}
+	return degrees(2 * math.Asin(r/d))
+}
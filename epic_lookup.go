@@ -0,0 +1,154 @@
+package nasa
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// EPICImageName is a parsed EPIC image name, such as epic_1b_20200424002712.
+type EPICImageName struct {
+	// Name is the image name without directory or extension.
+	Name string
+
+	Collection EPICCollection
+
+	// Time is when the image was taken, in UTC.
+	Time time.Time
+
+	// Variant is the format named by the extension and directory; bare
+	// names are EPICImagePNG.
+	Variant EPICImageVariant
+}
+
+var (
+	epicNamePattern = regexp.MustCompile(`^epic_([A-Za-z0-9]+)_(\d{14})$`)
+	epicIDPattern   = regexp.MustCompile(`^\d{14}$`)
+)
+
+// ParseEPICImageName parses an EPIC image name. The name may carry an
+// extension and the archive directories, or be a full archive URL:
+//
+//	epic_1b_20200424002712
+//	epic_RGB_20200424002712.png
+//	https://epic.gsfc.nasa.gov/archive/enhanced/2020/04/24/thumbs/epic_RGB_20200424002712.jpg
+func ParseEPICImageName(s string) (EPICImageName, error) {
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+
+	file := path.Base(s)
+	ext := path.Ext(file)
+	n := EPICImageName{Name: strings.TrimSuffix(file, ext)}
+
+	switch strings.ToLower(ext) {
+	case "", ".png":
+		n.Variant = EPICImagePNG
+	case ".jpg":
+		n.Variant = EPICImageJPG
+		if path.Base(path.Dir(s)) == "thumbs" {
+			n.Variant = EPICImageThumb
+		}
+	default:
+		return EPICImageName{}, ErrorInvalidEPICName
+	}
+
+	m := epicNamePattern.FindStringSubmatch(n.Name)
+	if m == nil {
+		return EPICImageName{}, ErrorInvalidEPICName
+	}
+
+	for c, token := range epicNameTokens {
+		if strings.EqualFold(token, m[1]) {
+			n.Collection = c
+		}
+	}
+	if n.Collection == "" {
+		return EPICImageName{}, ErrorInvalidEPICName
+	}
+
+	t, err := time.Parse("20060102150405", m[2])
+	if err != nil {
+		return EPICImageName{}, ErrorInvalidEPICName
+	}
+	n.Time = t
+
+	return n, nil
+}
+
+// EPICImageByID gets the EPIC image with the given identifier, such as
+// 20200424003633, or image name, such as epic_1b_20200424002712.
+func EPICImageByID(p ParamEncoder, id string) (*EPICImage, error) {
+	return DefaultClient.EPICImageByID(p, id)
+}
+
+// EPICImageByIDContext is like EPICImageByID but uses the given context.
+func EPICImageByIDContext(ctx context.Context, p ParamEncoder, id string) (*EPICImage, error) {
+	return DefaultClient.EPICImageByIDContext(ctx, p, id)
+}
+
+// EPICImageByID gets the EPIC image with the given identifier, such as
+// 20200424003633, or image name, such as epic_1b_20200424002712.
+//
+// The date is taken from the identifier and the listing of that day fetched
+// with p, an *EPICParams whose Date is ignored. Image names select their own
+// collection; identifiers use the Collection of p. Identifiers are stamped
+// when an image was processed, which may be the day after it was taken, so
+// the listing of the day before is searched too. Listings are cached by the
+// client Cache, if any, so looking up many images of a day with WithCache
+// fetches its listing once. An error matching ErrorNotFound is returned if
+// no listing has such an image.
+func (c *Client) EPICImageByID(p ParamEncoder, id string) (*EPICImage, error) {
+	return c.EPICImageByIDContext(context.Background(), p, id)
+}
+
+// EPICImageByIDContext is like EPICImageByID but uses the given context.
+func (c *Client) EPICImageByIDContext(ctx context.Context, p ParamEncoder, id string) (*EPICImage, error) {
+	params, ok := p.(*EPICParams)
+	if !ok {
+		return nil, ErrorParamsMismatch
+	}
+	day := *params
+
+	name := ""
+	if n, err := ParseEPICImageName(id); err == nil {
+		name = n.Name
+		day.Date = n.Time
+		day.Collection = n.Collection
+	} else if epicIDPattern.MatchString(id) {
+		t, err := time.Parse("20060102150405", id)
+		if err != nil {
+			return nil, ErrorInvalidEPICName
+		}
+		day.Date = t
+	} else {
+		return nil, ErrorInvalidEPICName
+	}
+
+	// Only the day selects the listing.
+	day.Date = time.Date(day.Date.Year(), day.Date.Month(), day.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+	days := []time.Time{day.Date}
+	if name == "" {
+		days = append(days, day.Date.AddDate(0, 0, -1))
+	}
+
+	for _, d := range days {
+		day.Date = d
+		images, err := c.EPICContext(ctx, &day)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, img := range images {
+			if (name != "" && img.Image == name) || (name == "" && img.Identifier == id) {
+				return img, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("EPIC image %s: %w", id, ErrorNotFound)
+}
@@ -1,6 +1,17 @@
 package nasa
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"time"
@@ -16,7 +27,7 @@ func TestBuildNaturalURLs(t *testing.T) {
 		Date:  EPICDate{Time: time.Date(2020, 4, 24, 0, 0, 0, 0, time.UTC)},
 	}
 
-	e.buildNaturalURLs(p)
+	e.buildNaturalURLs(epicAPIURL, p.APIKey)
 
 	if e.URL.Natural != full {
 		t.Errorf("\nexpected: %s\ngot: %s", full, e.URL.Natural)
@@ -37,7 +48,7 @@ func TestBuildEnhancedURLs(t *testing.T) {
 		Date:  EPICDate{Time: time.Date(2020, 4, 24, 0, 0, 0, 0, time.UTC)},
 	}
 
-	e.buildEnhancedURLs(p)
+	e.buildEnhancedURLs(epicAPIURL, p.APIKey)
 
 	if e.URL.Enhanced != full {
 		t.Errorf("\nexpected: %s\ngot: %s", full, e.URL.Enhanced)
@@ -47,3 +58,658 @@ func TestBuildEnhancedURLs(t *testing.T) {
 		t.Errorf("\nexpected: %s\ngot: %s", thumb, e.URL.Thumb.Enhanced)
 	}
 }
+
+func TestKeylessEPICURLs(t *testing.T) {
+	full := "https://epic.gsfc.nasa.gov/archive/natural/2020/04/24/png/epic_1b_20200424002712.png"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"image":"epic_1b_20200424002712","date":"2020-04-24 00:27:12"}]`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithEPICURL(ts.URL), WithKeylessEPICURLs())
+	images, err := c.EPIC(&EPICParams{APIKey: "NASA_KEY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if images[0].URL.Natural != full {
+		t.Errorf("\nexpected: %s\ngot: %s", full, images[0].URL.Natural)
+	}
+
+	if strings.Contains(images[0].URL.Thumb.Enhanced, "api_key") {
+		t.Errorf("unexpected API key in %s", images[0].URL.Thumb.Enhanced)
+	}
+}
+
+func TestDownloadEPICImage(t *testing.T) {
+	var gotPath, gotKey string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.URL.Query().Get("api_key")
+		w.Write([]byte("image data"))
+	}))
+	defer ts.Close()
+
+	e := &EPICImage{
+		Image: "epic_1b_20200424002712",
+		Date:  EPICDate{Time: time.Date(2020, 4, 24, 0, 0, 0, 0, time.UTC)},
+	}
+
+	t.Run("resolves key", func(t *testing.T) {
+		c := NewClient(WithEPICURL(ts.URL), WithAPIKey("NASA_KEY"))
+
+		var b bytes.Buffer
+		n, err := c.DownloadEPICImage(e, EPICEnhancedThumb, &b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n != int64(b.Len()) || b.String() != "image data" {
+			t.Errorf("unexpected content: %q", b.String())
+		}
+
+		expected := "/archive/enhanced/2020/04/24/thumbs/epic_RGB_20200424002712.jpg"
+		if gotPath != expected {
+			t.Errorf("\nexpected: %s\ngot: %s", expected, gotPath)
+		}
+
+		if gotKey != "NASA_KEY" {
+			t.Errorf("expected: NASA_KEY, got: %s", gotKey)
+		}
+	})
+
+	t.Run("keyless archive", func(t *testing.T) {
+		c := NewClient(WithEPICArchiveURL(ts.URL), WithAPIKey("NASA_KEY"))
+
+		var b bytes.Buffer
+		if _, err := c.DownloadEPICImage(e, EPICNatural, &b); err != nil {
+			t.Fatal(err)
+		}
+
+		if gotKey != "" {
+			t.Errorf("expected no API key, got: %s", gotKey)
+		}
+	})
+}
+
+func TestEPICCollections(t *testing.T) {
+	var gotPath string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(`[{"image":"epic_uvai_20200424002712","date":"2020-04-24 00:27:12"}]`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithEPICURL(ts.URL), WithAPIKey("NASA_KEY"), WithKeylessEPICURLs())
+	images, err := c.EPIC(&EPICParams{Collection: EPICCollectionAerosol})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != "/api/aerosol" {
+		t.Errorf("expected: /api/aerosol, got: %s", gotPath)
+	}
+
+	img := images[0]
+	if img.Collection != EPICCollectionAerosol {
+		t.Errorf("expected: aerosol, got: %s", img.Collection)
+	}
+
+	full := "https://epic.gsfc.nasa.gov/archive/aerosol/2020/04/24/png/epic_uvai_20200424002712.png"
+	if img.URL.Image != full {
+		t.Errorf("\nexpected: %s\ngot: %s", full, img.URL.Image)
+	}
+
+	thumb := "https://epic.gsfc.nasa.gov/archive/aerosol/2020/04/24/thumbs/epic_uvai_20200424002712.jpg"
+	if img.URL.Thumb.Image != thumb {
+		t.Errorf("\nexpected: %s\ngot: %s", thumb, img.URL.Thumb.Image)
+	}
+
+	if img.URL.Natural != "" || img.URL.Enhanced != "" {
+		t.Errorf("unexpected natural or enhanced URLs: %+v", img.URL)
+	}
+
+	t.Run("variants", func(t *testing.T) {
+		e := &EPICImage{
+			Image: "epic_RGB_20200424002712",
+			Date:  EPICDate{Time: time.Date(2020, 4, 24, 0, 0, 0, 0, time.UTC)},
+		}
+
+		tests := []struct {
+			v        EPICImageVariant
+			expected string
+		}{
+			{EPICNatural, "/archive/natural/2020/04/24/png/epic_1b_20200424002712.png"},
+			{EPICEnhancedThumb, "/archive/enhanced/2020/04/24/thumbs/epic_RGB_20200424002712.jpg"},
+			{EPICImageJPG, "/archive/enhanced/2020/04/24/jpg/epic_RGB_20200424002712.jpg"},
+		}
+
+		for _, tt := range tests {
+			u, err := e.archiveURL("", "", tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if u != tt.expected {
+				t.Errorf("\nexpected: %s\ngot: %s", tt.expected, u)
+			}
+		}
+	})
+}
+
+func TestEPICDates(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.URL.Query().Get("api_key"); key != "NASA_KEY" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/enhanced/available":
+			w.Write([]byte(`["2020-04-24","2015-06-13","2020-04-21"]`))
+		case "/api/enhanced/all":
+			w.Write([]byte(`[{"date":"2020-04-24 00:00:00"},{"date":"2015-06-13"},{"date":"2020-04-21"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithEPICURL(ts.URL), WithAPIKey("NASA_KEY"))
+	expected := []time.Time{
+		time.Date(2015, 6, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 4, 21, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 4, 24, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name  string
+		dates func(ParamEncoder) ([]time.Time, error)
+	}{
+		{"available", c.EPICAvailableDates},
+		{"all", c.EPICAllDates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dates, err := tt.dates(&EPICParams{Collection: EPICCollectionEnhanced})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(dates, expected) {
+				t.Errorf("expected: %v, got: %v", expected, dates)
+			}
+		})
+	}
+
+	t.Run("params key", func(t *testing.T) {
+		c := NewClient(WithEPICURL(ts.URL))
+		_, err := c.EPICAvailableDates(&EPICParams{APIKey: "NASA_KEY", Collection: EPICCollectionEnhanced})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		c := NewClient(WithEPICURL(ts.URL))
+		_, err := c.EPICAvailableDates(&EPICParams{Collection: EPICCollectionEnhanced})
+		if err != ErrorNoAPIKey {
+			t.Errorf("wrong error returned: %v", err)
+		}
+	})
+
+	t.Run("unknown collection", func(t *testing.T) {
+		_, err := c.EPICAvailableDates(&EPICParams{Collection: "infrared"})
+		if err != ErrorUnknownEPICCollection {
+			t.Errorf("wrong error returned: %v", err)
+		}
+	})
+
+	t.Run("params mismatch", func(t *testing.T) {
+		_, err := c.EPICAvailableDates(&APIParam{})
+		if err != ErrorParamsMismatch {
+			t.Errorf("wrong error returned: %v", err)
+		}
+	})
+}
+
+func TestNearestEPICDate(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2020, m, d, 0, 0, 0, 0, time.UTC) }
+	dates := []time.Time{day(4, 10), day(4, 14), day(4, 20)}
+
+	tests := []struct {
+		date     time.Time
+		expected time.Time
+	}{
+		{day(4, 1), day(4, 10)},
+		{day(4, 10), day(4, 10)},
+		{day(4, 11), day(4, 10)},
+		{day(4, 12), day(4, 10)},
+		{day(4, 13), day(4, 14)},
+		{day(4, 18), day(4, 20)},
+		{day(5, 1), day(4, 20)},
+	}
+
+	for _, tt := range tests {
+		got, ok := NearestEPICDate(dates, tt.date)
+		if !ok || !got.Equal(tt.expected) {
+			t.Errorf("%s: expected: %s, got: %s", tt.date.Format("2006-01-02"), tt.expected.Format("2006-01-02"), got.Format("2006-01-02"))
+		}
+	}
+
+	if _, ok := NearestEPICDate(nil, day(4, 1)); ok {
+		t.Error("expected no date from an empty list")
+	}
+}
+
+func TestEPICGeometry(t *testing.T) {
+	// Positions chosen so that every value is known exactly: DSCOVR 30°
+	// from the Sun on a 3-4-5 triangle, the Moon on a 5-12-13 one, and a
+	// sphere seen from twice its radius spanning 60°.
+	e := &EPICImage{}
+	e.Coords.Dscovr = XYZ{X: 1500000 * math.Sqrt(3) / 2, Y: 1500000 / 2}
+	e.Coords.Lunar = XYZ{X: 5 * 29600, Z: 12 * 29600}
+	e.Coords.Sun = XYZ{X: 150000000}
+
+	near := &EPICImage{}
+	near.Coords.Dscovr = XYZ{X: 3 * earthRadius * 2 / 5, Y: 4 * earthRadius * 2 / 5}
+
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"DSCOVR distance", e.DscovrDistance(), 1500000},
+		{"Moon distance", e.MoonDistance(), 13 * 29600},
+		{"SEV angle", e.SEVAngle(), 30},
+		{"Earth angular size", near.EarthAngularSize(), 60},
+	}
+
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.expected) > 1e-9*tt.expected {
+			t.Errorf("%s: expected: %f, got: %f", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	// From about 1.5 million km out at L1, Earth spans about half a degree,
+	// which the 0.61° EPIC field of view just frames.
+	if size := e.EarthAngularSize(); size < 0.48 || size > 0.49 {
+		t.Errorf("expected: about 0.487, got: %f", size)
+	}
+
+	// The Moon is some 15° off the line of sight.
+	if e.MoonInFrame() {
+		t.Error("expected the Moon out of frame")
+	}
+
+	// A lunar transit, with the Moon crossing between DSCOVR and Earth.
+	transit := &EPICImage{}
+	transit.Coords.Dscovr = XYZ{X: 1500000}
+	transit.Coords.Lunar = XYZ{X: 380000, Y: 3000}
+	if !transit.MoonInFrame() {
+		t.Error("expected the Moon in frame")
+	}
+}
+
+func TestQuaternionsRotationMatrix(t *testing.T) {
+	t.Run("z axis", func(t *testing.T) {
+		// 90 degrees about the z axis turns x into y.
+		q := Quaternions{Q0: math.Sqrt2 / 2, Q3: math.Sqrt2 / 2}
+		m := q.RotationMatrix()
+
+		expected := [3][3]float64{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}}
+		for i := range m {
+			for j := range m[i] {
+				if math.Abs(m[i][j]-expected[i][j]) > 1e-12 {
+					t.Fatalf("expected: %v, got: %v", expected, m)
+				}
+			}
+		}
+	})
+
+	t.Run("attitude", func(t *testing.T) {
+		// Attitudes are given to five decimals, so are not quite unit
+		// quaternions; the matrix must still be a rotation.
+		q := Quaternions{Q0: -0.31526, Q1: 0.20133, Q2: 0.13245, Q3: 0.91734}
+		m := q.RotationMatrix()
+
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				dot := m[i][0]*m[j][0] + m[i][1]*m[j][1] + m[i][2]*m[j][2]
+				expected := 0.0
+				if i == j {
+					expected = 1
+				}
+				if math.Abs(dot-expected) > 1e-12 {
+					t.Errorf("rows %d and %d: expected: %f, got: %f", i, j, expected, dot)
+				}
+			}
+		}
+
+		det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+			m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+			m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+		if math.Abs(det-1) > 1e-12 {
+			t.Errorf("expected: 1, got: %f", det)
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		m := Quaternions{}.RotationMatrix()
+		if m != [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+			t.Errorf("expected the identity, got: %v", m)
+		}
+	})
+}
+
+func TestEPICCovering(t *testing.T) {
+	img := func(name string, lon float64) *EPICImage {
+		e := &EPICImage{Image: name}
+		e.Date = EPICDate{Time: time.Date(2020, 4, 24, 0, 27, 12, 0, time.UTC)}
+		e.Coords.Centroid = LatLon{Lat: 7.3, Lon: lon}
+		e.Coords.Dscovr = XYZ{X: 1245892.5, Y: 742077.25, Z: 185788.22}
+		return e
+	}
+	images := EPICImages{img("far", 120), img("near", 171.5), img("middle", 150)}
+
+	// Honolulu is about 33 degrees from the centroid of "near".
+	honolulu := LatLon{Lat: 21.3, Lon: -157.9}
+	covs := images.Covering(honolulu)
+
+	order := ""
+	for _, c := range covs {
+		order += c.Image.Image + " "
+	}
+	if order != "near middle far " {
+		t.Errorf("expected: near middle far, got: %s", order)
+	}
+
+	if d := covs[0].Distance; math.Abs(d-32.69) > 0.01 {
+		t.Errorf("expected: 32.69, got: %f", d)
+	}
+	if !covs[0].Visible() {
+		t.Errorf("expected Honolulu visible: %+v", covs[0])
+	}
+	if covs[0].X <= EPICImageSize/2 || covs[0].Y >= EPICImageSize/2 {
+		t.Errorf("expected Honolulu north east of the center, got: %f, %f", covs[0].X, covs[0].Y)
+	}
+
+	t.Run("centroid", func(t *testing.T) {
+		c := images[1].Locate(images[1].Coords.Centroid)
+		if c.Distance != 0 || !c.Visible() || c.X != EPICImageSize/2 || c.Y != EPICImageSize/2 {
+			t.Errorf("unexpected coverage: %+v", c)
+		}
+	})
+
+	t.Run("pixel", func(t *testing.T) {
+		// 30 degrees north of the centroid, 1,462,000 km away.
+		c := images[1].Locate(LatLon{Lat: 37.3, Lon: 171.5})
+		if math.Abs(c.X-1024) > 0.001 || math.Abs(c.Y-603) > 1 {
+			t.Errorf("expected: 1024, 603, got: %f, %f", c.X, c.Y)
+		}
+	})
+
+	t.Run("far side", func(t *testing.T) {
+		// Over Africa at 00:27 UTC it is night, facing away from DSCOVR.
+		c := images[1].Locate(LatLon{Lat: 0, Lon: 20})
+		if c.OnDisk || c.Sunlit || c.Visible() {
+			t.Errorf("unexpected coverage: %+v", c)
+		}
+	})
+
+	t.Run("limb", func(t *testing.T) {
+		// Just inside the limb, seen from finitely far away.
+		c := images[1].Locate(LatLon{Lat: 7.3, Lon: 171.5 + 89.5})
+		if !c.OnDisk {
+			t.Errorf("expected on disk: %+v", c)
+		}
+		if r := c.X - EPICImageSize/2; r < 700 || r > 900 {
+			t.Errorf("expected near the edge of the disk, got: %f", r)
+		}
+	})
+}
+
+func TestDownloadEPICImages(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if strings.Contains(r.URL.Path, "/aerosol/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "epic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	images := EPICImages{}
+	for _, ts := range []string{"002712", "021515"} {
+		images = append(images, &EPICImage{
+			Image: "epic_1b_20200424" + ts,
+			Date:  EPICDate{Time: time.Date(2020, 4, 24, 0, 0, 0, 0, time.UTC)},
+		})
+	}
+
+	c := NewClient(WithEPICArchiveURL(ts.URL))
+	opts := &EPICDownloadOptions{
+		Variants:    []EPICImageVariant{EPICImagePNG, EPICImageThumb},
+		Collections: []EPICCollection{EPICCollectionNatural, EPICCollectionEnhanced},
+		Concurrency: 3,
+	}
+
+	results, err := c.DownloadEPICImages(images, dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := atomic.LoadInt32(&requests); len(results) != 8 || n != 8 {
+		t.Fatalf("expected: 8 results and requests, got: %d, %d", len(results), n)
+	}
+
+	r := results[3]
+	expected := filepath.Join(dir, "enhanced", "2020", "04", "24", "thumbs", "epic_RGB_20200424002712.jpg")
+	if r.Image != images[0] || r.Path != expected || r.Skipped || r.Err != nil {
+		t.Errorf("unexpected result: %+v", r)
+	}
+
+	b, err := ioutil.ReadFile(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "/archive/enhanced/2020/04/24/thumbs/epic_RGB_20200424002712.jpg" || r.Size != int64(len(b)) {
+		t.Errorf("unexpected content: %q", b)
+	}
+
+	t.Run("skips existing", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+
+		results, err := c.DownloadEPICImages(images, dir, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range results {
+			if !r.Skipped || r.Size == 0 {
+				t.Errorf("expected skipped: %+v", r)
+			}
+		}
+		if n := atomic.LoadInt32(&requests); n != 0 {
+			t.Errorf("expected: 0 requests, got: %d", n)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		opts := &EPICDownloadOptions{Collections: []EPICCollection{EPICCollectionAerosol, EPICCollectionNatural}}
+
+		results, err := c.DownloadEPICImages(images[:1], dir, opts)
+		if !errors.Is(err, ErrorNotFound) {
+			t.Errorf("expected ErrorNotFound, got: %v", err)
+		}
+
+		if len(results) != 2 || !errors.Is(results[0].Err, ErrorNotFound) || !results[1].Skipped {
+			t.Errorf("unexpected results: %+v", results)
+		}
+
+		if _, err := os.Stat(results[0].Path + ".part"); !os.IsNotExist(err) {
+			t.Errorf("expected the partial file removed, got: %v", err)
+		}
+	})
+
+	t.Run("bad options", func(t *testing.T) {
+		_, err := c.DownloadEPICImages(images, dir, &EPICDownloadOptions{Variants: []EPICImageVariant{EPICNatural}})
+		if err == nil {
+			t.Error("expected an error")
+		}
+
+		_, err = c.DownloadEPICImages(images, dir, &EPICDownloadOptions{Collections: []EPICCollection{"infrared"}})
+		if err != ErrorUnknownEPICCollection {
+			t.Errorf("wrong error returned: %v", err)
+		}
+	})
+}
+
+func TestParseEPICImageName(t *testing.T) {
+	taken := time.Date(2020, 4, 24, 0, 27, 12, 0, time.UTC)
+
+	tests := []struct {
+		in       string
+		expected EPICImageName
+	}{
+		{"epic_1b_20200424002712", EPICImageName{"epic_1b_20200424002712", EPICCollectionNatural, taken, EPICImagePNG}},
+		{"epic_RGB_20200424002712.png", EPICImageName{"epic_RGB_20200424002712", EPICCollectionEnhanced, taken, EPICImagePNG}},
+		{"aerosol/2020/04/24/jpg/epic_uvai_20200424002712.jpg", EPICImageName{"epic_uvai_20200424002712", EPICCollectionAerosol, taken, EPICImageJPG}},
+		{
+			"https://api.nasa.gov/EPIC/archive/cloud/2020/04/24/thumbs/epic_cloudfraction_20200424002712.jpg?api_key=NASA_KEY",
+			EPICImageName{"epic_cloudfraction_20200424002712", EPICCollectionCloud, taken, EPICImageThumb},
+		},
+	}
+
+	for _, tt := range tests {
+		n, err := ParseEPICImageName(tt.in)
+		if err != nil {
+			t.Errorf("%s: %v", tt.in, err)
+			continue
+		}
+		if n != tt.expected {
+			t.Errorf("\nexpected: %+v\ngot: %+v", tt.expected, n)
+		}
+	}
+
+	for _, in := range []string{"", "20200424003633", "epic_1b_2020", "epic_ir_20200424002712", "epic_1b_20201324002712", "epic_1b_20200424002712.gif"} {
+		if _, err := ParseEPICImageName(in); err != ErrorInvalidEPICName {
+			t.Errorf("%q: wrong error returned: %v", in, err)
+		}
+	}
+}
+
+func TestEPICImageByID(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/enhanced/date/2020-04-24":
+			w.Write([]byte(`[{"identifier":"20200424003633","image":"epic_RGB_20200424002712","date":"2020-04-24 00:27:12"}]`))
+		case "/api/natural/date/2020-04-24":
+			w.Write([]byte(`[
+				{"identifier":"20200424003633","image":"epic_1b_20200424002712","date":"2020-04-24 00:27:12"},
+				{"identifier":"20200424022436","image":"epic_1b_20200424021515","date":"2020-04-24 02:15:15"}
+			]`))
+		case "/api/natural/date/2020-04-23":
+			w.Write([]byte(`[{"identifier":"20200424000950","image":"epic_1b_20200423234903","date":"2020-04-23 23:49:03"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer ts.Close()
+
+	// The Cache fetches each listing once.
+	c := NewClient(WithEPICURL(ts.URL), WithAPIKey("NASA_KEY"), WithCache(NewMemoryCache(0)))
+
+	img, err := c.EPICImageByID(&EPICParams{}, "20200424022436")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Image != "epic_1b_20200424021515" || img.Collection != EPICCollectionNatural {
+		t.Errorf("unexpected image: %+v", img)
+	}
+
+	img, err = c.EPICImageByID(&EPICParams{}, "epic_1b_20200424002712")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Identifier != "20200424003633" {
+		t.Errorf("expected: 20200424003633, got: %s", img.Identifier)
+	}
+
+	img, err = c.EPICImageByID(&EPICParams{}, "epic_RGB_20200424002712.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Collection != EPICCollectionEnhanced {
+		t.Errorf("expected: enhanced, got: %s", img.Collection)
+	}
+
+	expected := []string{"/api/natural/date/2020-04-24", "/api/enhanced/date/2020-04-24"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected: %v, got: %v", expected, paths)
+	}
+
+	t.Run("previous day", func(t *testing.T) {
+		paths = nil
+		img, err := c.EPICImageByID(&EPICParams{}, "20200424000950")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Image != "epic_1b_20200423234903" {
+			t.Errorf("expected: epic_1b_20200423234903, got: %s", img.Image)
+		}
+
+		expected := []string{"/api/natural/date/2020-04-23"}
+		if !reflect.DeepEqual(paths, expected) {
+			t.Errorf("expected: %v, got: %v", expected, paths)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		paths = nil
+		_, err := c.EPICImageByID(&EPICParams{}, "20200424235959")
+		if !errors.Is(err, ErrorNotFound) {
+			t.Errorf("expected ErrorNotFound, got: %v", err)
+		}
+
+		if len(paths) != 0 {
+			t.Errorf("expected: no requests, got: %v", paths)
+		}
+	})
+
+	t.Run("without cache", func(t *testing.T) {
+		paths = nil
+		c := NewClient(WithEPICURL(ts.URL), WithAPIKey("NASA_KEY"))
+		for _, id := range []string{"20200424022436", "20200424003633"} {
+			if _, err := c.EPICImageByID(&EPICParams{}, id); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if len(paths) != 2 {
+			t.Errorf("expected: 2 requests, got: %v", paths)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := c.EPICImageByID(&EPICParams{}, "yesterday"); err != ErrorInvalidEPICName {
+			t.Errorf("wrong error returned: %v", err)
+		}
+
+		if _, err := c.EPICImageByID(&APIParam{}, "20200424003633"); err != ErrorParamsMismatch {
+			t.Errorf("wrong error returned: %v", err)
+		}
+	})
+}
@@ -1,8 +1,12 @@
 package nasa
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
 )
 
 var (
@@ -17,8 +21,147 @@ var (
 
 	// ErrorParamsMismatch is returned when the wrong type of ParamEncoder is used.
 	ErrorParamsMismatch = errors.New("wrong param type passed")
+
+	// ErrorAPODParamsConflict is returned when more than one of Date,
+	// StartDate/EndDate and Count are set on APODParams.
+	ErrorAPODParamsConflict = errors.New("APOD date, start/end dates and count are mutually exclusive")
+
+	// ErrorAPODDateOutOfRange is returned when an APOD date is before the first APOD or after today.
+	ErrorAPODDateOutOfRange = errors.New("APOD dates must be between 1995-06-16 and today")
+
+	// ErrorAPODInvalidRange is returned when an APOD end date is missing its start date or precedes it.
+	ErrorAPODInvalidRange = errors.New("APOD end date must follow a start date")
+
+	// ErrorAPODInvalidCount is returned when the APOD count is outside 1 to 100.
+	ErrorAPODInvalidCount = errors.New("APOD count must be between 1 and 100")
+
+	// ErrorAPODMultiple is returned when APOD is given params selecting more than one APOD.
+	ErrorAPODMultiple = errors.New("APOD params select several APODs; use APODList")
+
+	// ErrorAPODNoImage is returned when downloading an APOD which is not an image.
+	ErrorAPODNoImage = errors.New("APOD has no image to download")
+
+	// ErrorUnknownEPICCollection is returned when EPICParams has an unknown Collection.
+	ErrorUnknownEPICCollection = errors.New("unknown EPIC collection")
+
+	// ErrorInvalidEPICName is returned for malformed EPIC image names and identifiers.
+	ErrorInvalidEPICName = errors.New("invalid EPIC image name or identifier")
+
+	// ErrorRateLimited matches an APIError caused by exceeding the rate limit.
+	ErrorRateLimited = errors.New("rate limit exceeded")
+
+	// ErrorInvalidAPIKey matches an APIError caused by a missing or invalid API key.
+	ErrorInvalidAPIKey = errors.New("invalid API key")
+
+	// ErrorNotFound matches an APIError caused by a missing resource.
+	ErrorNotFound = errors.New("not found")
 )
 
+// APIError is returned when an API responds with a non-2xx status.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Code is the error code given by the API, if any (e.g. OVER_RATE_LIMIT).
+	Code string
+
+	// Message is the error message given by the API, if any.
+	Message string
+
+	// URL is the request URL with the API key redacted.
+	URL string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+
+	if e.Code != "" {
+		return fmt.Sprintf("GET %s: %d %s: %s", e.URL, e.StatusCode, e.Code, msg)
+	}
+	return fmt.Sprintf("GET %s: %d %s", e.URL, e.StatusCode, msg)
+}
+
+// Is reports whether the error matches one of ErrorRateLimited,
+// ErrorInvalidAPIKey or ErrorNotFound.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrorRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.Code == "OVER_RATE_LIMIT"
+	case ErrorInvalidAPIKey:
+		return e.Code == "API_KEY_INVALID" || e.Code == "API_KEY_MISSING"
+	case ErrorNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// apiErrorResponse covers the error bodies returned by the different APIs.
+type apiErrorResponse struct {
+	// api.nasa.gov gateway: {"error":{"code":"...","message":"..."}}
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+
+	// APOD: {"code":400,"msg":"..."}
+	Code interface{} `json:"code"`
+	Msg  string      `json:"msg"`
+
+	// images-api: {"reason":"..."}
+	Reason string `json:"reason"`
+
+	// Mars Rover Photos: {"errors":"..."}
+	Errors string `json:"errors"`
+}
+
+func newAPIError(u *url.URL, statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		URL:        redactURL(u),
+	}
+
+	r := apiErrorResponse{}
+	if err := json.Unmarshal(body, &r); err != nil {
+		return e
+	}
+
+	switch {
+	case r.Error != nil:
+		e.Code = r.Error.Code
+		e.Message = r.Error.Message
+	case r.Msg != "":
+		if r.Code != nil {
+			e.Code = fmt.Sprint(r.Code)
+		}
+		e.Message = r.Msg
+	case r.Reason != "":
+		e.Message = r.Reason
+	case r.Errors != "":
+		e.Message = r.Errors
+	}
+
+	return e
+}
+
+var apiKeyParam = regexp.MustCompile(`(api_key=)[^&#"'\s]*`)
+
+// RedactURL returns s with the value of every api_key query param replaced
+// by REDACTED. It works on any text containing URLs, not only a single URL.
+func RedactURL(s string) string {
+	return apiKeyParam.ReplaceAllString(s, "${1}REDACTED")
+}
+
+// redactURL returns u as a string with the api_key query param redacted.
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return RedactURL(u.String())
+}
+
 // ErrorRoverCameraMissing is returned if the rover does not have the camera available.
 type ErrorRoverCameraMissing struct {
 	rover  Rover
@@ -0,0 +1,146 @@
+package nasa
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		code    string
+		message string
+		kind    error
+	}{
+		{
+			name:    "invalid key",
+			status:  http.StatusForbidden,
+			body:    `{"error":{"code":"API_KEY_INVALID","message":"An invalid api_key was supplied."}}`,
+			code:    "API_KEY_INVALID",
+			message: "An invalid api_key was supplied.",
+			kind:    ErrorInvalidAPIKey,
+		},
+		{
+			name:    "rate limited",
+			status:  http.StatusTooManyRequests,
+			body:    `{"error":{"code":"OVER_RATE_LIMIT","message":"You have exceeded your rate limit."}}`,
+			code:    "OVER_RATE_LIMIT",
+			message: "You have exceeded your rate limit.",
+			kind:    ErrorRateLimited,
+		},
+		{
+			name:    "APOD",
+			status:  http.StatusBadRequest,
+			body:    `{"code":400,"msg":"Date must be between Jun 16, 1995 and Apr 29, 2020.","service_version":"v1"}`,
+			code:    "400",
+			message: "Date must be between Jun 16, 1995 and Apr 29, 2020.",
+		},
+		{
+			name:    "images-api",
+			status:  http.StatusNotFound,
+			body:    `{"reason":"No captions found."}`,
+			message: "No captions found.",
+			kind:    ErrorNotFound,
+		},
+		{
+			name:    "mars",
+			status:  http.StatusBadRequest,
+			body:    `{"errors":"Invalid Rover Name"}`,
+			message: "Invalid Rover Name",
+		},
+		{
+			name:   "not JSON",
+			status: http.StatusBadGateway,
+			body:   `<html>Bad Gateway</html>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer ts.Close()
+
+			c := NewClient(WithAPODURL(ts.URL))
+			_, err := c.APOD(&APODParams{APIKey: "NASA_KEY"})
+
+			apiErr := &APIError{}
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got: %v", err)
+			}
+
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("expected: %d, got: %d", tt.status, apiErr.StatusCode)
+			}
+
+			if apiErr.Code != tt.code {
+				t.Errorf("expected: %s, got: %s", tt.code, apiErr.Code)
+			}
+
+			if apiErr.Message != tt.message {
+				t.Errorf("expected: %s, got: %s", tt.message, apiErr.Message)
+			}
+
+			if strings.Contains(apiErr.URL, "NASA_KEY") || strings.Contains(err.Error(), "NASA_KEY") {
+				t.Errorf("API key not redacted: %s", err)
+			}
+
+			if tt.kind != nil && !errors.Is(err, tt.kind) {
+				t.Errorf("expected error to match %v", tt.kind)
+			}
+		})
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{
+			"https://api.nasa.gov/planetary/apod?api_key=NASA_KEY&date=2020-04-29",
+			"https://api.nasa.gov/planetary/apod?api_key=REDACTED&date=2020-04-29",
+		},
+		{
+			"https://api.nasa.gov/EPIC/archive/natural/2020/04/24/png/epic_1b_20200424002712.png?api_key=NASA_KEY",
+			"https://api.nasa.gov/EPIC/archive/natural/2020/04/24/png/epic_1b_20200424002712.png?api_key=REDACTED",
+		},
+		{
+			`Get "https://api.nasa.gov/EPIC/api/natural?api_key=NASA_KEY": EOF`,
+			`Get "https://api.nasa.gov/EPIC/api/natural?api_key=REDACTED": EOF`,
+		},
+		{
+			"https://images-api.nasa.gov/search?q=apollo",
+			"https://images-api.nasa.gov/search?q=apollo",
+		},
+	}
+
+	for _, tt := range tests {
+		got := RedactURL(tt.in)
+		if got != tt.expected {
+			t.Errorf("\nexpected: %s\ngot: %s", tt.expected, got)
+		}
+	}
+}
+
+func TestTransportErrorRedacted(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Close()
+
+	c := NewClient(WithAPODURL(ts.URL))
+	_, err := c.APOD(&APODParams{APIKey: "NASA_KEY"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if strings.Contains(err.Error(), "NASA_KEY") {
+		t.Errorf("API key not redacted: %s", err)
+	}
+}
@@ -0,0 +1,87 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Subtitle  string      `xml:"subtitle"`
+	Author    atomPerson  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Links     []atomLink  `xml:"link"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published"`
+	Rights    string      `xml:"rights,omitempty"`
+	Links     []atomLink  `xml:"link"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteAtom writes images to w as an Atom 1.0 feed, newest first. Each entry
+// links to its apod.nasa.gov page, has an ID from GUID, attaches the image
+// as an enclosure link, and credits APODImage.Copyright in rights.
+func (f *Feed) WriteAtom(w io.Writer, images []nasa.APODImage) error {
+	d := f.withDefaults()
+	images = sorted(images)
+
+	doc := atomFeed{
+		Title:     d.Title,
+		ID:        d.ID,
+		Updated:   updated(images).Format(time.RFC3339),
+		Subtitle:  d.Description,
+		Author:    atomPerson{Name: d.Author},
+		Generator: "nasa-go/" + nasa.Version,
+		Links:     []atomLink{{Href: d.Link, Rel: "alternate", Type: "text/html"}},
+	}
+
+	for i := range images {
+		img := &images[i]
+		date := img.Date.Format(time.RFC3339)
+
+		entry := atomEntry{
+			Title:     img.Title,
+			ID:        GUID(img.Date.Time),
+			Updated:   date,
+			Published: date,
+			Rights:    copyright(img),
+			Links:     []atomLink{{Href: APODPageURL(img.Date.Time), Rel: "alternate", Type: "text/html"}},
+			Content:   atomContent{Type: "html", Value: d.content(img)},
+		}
+
+		if u := d.imageURL(img); u != "" {
+			entry.Links = append(entry.Links, atomLink{Href: u, Rel: "enclosure", Type: imageType(u)})
+		}
+
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return writeXML(w, doc)
+}
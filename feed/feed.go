@@ -0,0 +1,166 @@
+// Package feed generates RSS 2.0 and Atom 1.0 feeds of Astronomy Pictures
+// Of the Day.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+// Defaults used for empty Feed fields.
+const (
+	DefaultTitle       = "Astronomy Picture of the Day"
+	DefaultLink        = "https://apod.nasa.gov/apod/"
+	DefaultDescription = "Each day a different image or photograph of our fascinating universe is featured, along with a brief explanation written by a professional astronomer."
+	DefaultAuthor      = "NASA"
+)
+
+// Feed describes the feed APODs are published in.
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	Author      string
+
+	// ID is the Atom feed ID. It defaults to Link.
+	ID string
+
+	// HD uses HDURL rather than URL for images.
+	HD bool
+}
+
+var imageTypes = map[string]string{
+	".gif":  "image/gif",
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".webp": "image/webp",
+}
+
+// APODPageURL returns the apod.nasa.gov page of the APOD for date.
+func APODPageURL(date time.Time) string {
+	return DefaultLink + "ap" + date.Format("060102") + ".html"
+}
+
+// GUID returns the stable ID of the APOD for date, a tag URI.
+func GUID(date time.Time) string {
+	return "tag:apod.nasa.gov," + date.Format("2006-01-02") + ":apod"
+}
+
+func (f *Feed) withDefaults() Feed {
+	d := *f
+	if d.Title == "" {
+		d.Title = DefaultTitle
+	}
+	if d.Link == "" {
+		d.Link = DefaultLink
+	}
+	if d.Description == "" {
+		d.Description = DefaultDescription
+	}
+	if d.Author == "" {
+		d.Author = DefaultAuthor
+	}
+	if d.ID == "" {
+		d.ID = d.Link
+	}
+	return d
+}
+
+// sorted returns a copy of images, newest first.
+func sorted(images []nasa.APODImage) []nasa.APODImage {
+	s := append([]nasa.APODImage(nil), images...)
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].Date.After(s[j].Date.Time)
+	})
+	return s
+}
+
+// updated returns the date of the newest APOD, or now if there are none.
+func updated(images []nasa.APODImage) time.Time {
+	if len(images) == 0 {
+		return time.Now().UTC()
+	}
+	return images[0].Date.Time
+}
+
+// copyright returns the cleaned up credit of img; empty for public domain images.
+func copyright(img *nasa.APODImage) string {
+	return strings.Join(strings.Fields(img.Copyright), " ")
+}
+
+// imageURL returns the image to attach to img, if any. Videos use their thumbnail.
+func (f *Feed) imageURL(img *nasa.APODImage) string {
+	if img.IsVideo() {
+		return img.ThumbnailURL
+	}
+	if f.HD && img.HDURL != "" {
+		return img.HDURL
+	}
+	return img.URL
+}
+
+// imageType returns the media type of the image at u from its extension.
+func imageType(u string) string {
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	if t, ok := imageTypes[strings.ToLower(path.Ext(u))]; ok {
+		return t
+	}
+	return "image/jpeg"
+}
+
+// content returns the HTML body of the entry for img.
+func (f *Feed) content(img *nasa.APODImage) string {
+	b := &strings.Builder{}
+
+	if img.IsVideo() {
+		if u := f.imageURL(img); u != "" {
+			fmt.Fprintf(b, `<p><a href="%s"><img src="%s" alt="%s"></a></p>`,
+				html.EscapeString(img.URL), html.EscapeString(u), html.EscapeString(img.Title))
+		} else {
+			fmt.Fprintf(b, `<p><a href="%s">Watch the video</a></p>`, html.EscapeString(img.URL))
+		}
+	} else if u := f.imageURL(img); u != "" {
+		link := img.HDURL
+		if link == "" {
+			link = u
+		}
+		fmt.Fprintf(b, `<p><a href="%s"><img src="%s" alt="%s"></a></p>`,
+			html.EscapeString(link), html.EscapeString(u), html.EscapeString(img.Title))
+	}
+
+	if img.Explanation != "" {
+		fmt.Fprintf(b, "<p>%s</p>", html.EscapeString(img.Explanation))
+	}
+
+	if c := copyright(img); c != "" {
+		fmt.Fprintf(b, "<p>Image Credit &amp; Copyright: %s</p>", html.EscapeString(c))
+	}
+
+	return b.String()
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,122 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+var testImages = []nasa.APODImage{
+	{
+		Date:         nasa.Date{Time: time.Date(2020, 4, 28, 0, 0, 0, 0, time.UTC)},
+		Title:        "Launch Video",
+		URL:          "https://www.youtube.com/embed/dQw4w9WgXcQ?rel=0",
+		ThumbnailURL: "https://img.youtube.com/vi/dQw4w9WgXcQ/0.jpg",
+		MediaType:    "video",
+	},
+	{
+		Date:        nasa.Date{Time: time.Date(2020, 4, 29, 0, 0, 0, 0, time.UTC)},
+		Title:       "Spiral Galaxy",
+		URL:         "https://apod.nasa.gov/apod/image/2004/galaxy1024.jpg",
+		HDURL:       "https://apod.nasa.gov/apod/image/2004/galaxy.png",
+		Explanation: "Stars & dust.",
+		MediaType:   "image",
+		Copyright:   "\nJane  Doe\n",
+	},
+}
+
+func TestWriteRSS(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := (&Feed{HD: true}).WriteRSS(buf, testImages); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := rss{}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Channel.Title != DefaultTitle {
+		t.Errorf("expected: %s, got: %s", DefaultTitle, doc.Channel.Title)
+	}
+
+	if len(doc.Channel.Items) != 2 {
+		t.Fatalf("expected 2 items, got: %d", len(doc.Channel.Items))
+	}
+
+	item := doc.Channel.Items[0]
+	if item.GUID.Value != "tag:apod.nasa.gov,2020-04-29:apod" {
+		t.Errorf("expected newest first, got: %s", item.GUID.Value)
+	}
+
+	if item.Link != "https://apod.nasa.gov/apod/ap200429.html" {
+		t.Errorf("expected: https://apod.nasa.gov/apod/ap200429.html, got: %s", item.Link)
+	}
+
+	if item.Enclosure == nil || item.Enclosure.URL != testImages[1].HDURL || item.Enclosure.Type != "image/png" {
+		t.Errorf("unexpected enclosure: %+v", item.Enclosure)
+	}
+
+	if !strings.Contains(buf.String(), "<dc:rights>Jane Doe</dc:rights>") {
+		t.Error("expected copyright in dc:rights")
+	}
+
+	if !strings.Contains(item.Description, "Image Credit &amp; Copyright: Jane Doe") ||
+		!strings.Contains(item.Description, "Stars &amp; dust.") {
+		t.Errorf("unexpected description: %s", item.Description)
+	}
+
+	video := doc.Channel.Items[1]
+	if video.Enclosure == nil || video.Enclosure.URL != testImages[0].ThumbnailURL {
+		t.Errorf("expected thumbnail enclosure, got: %+v", video.Enclosure)
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	buf := &bytes.Buffer{}
+	f := &Feed{Title: "Portal APOD", ID: "urn:example:apod"}
+	if err := f.WriteAtom(buf, testImages); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := atomFeed{}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Title != "Portal APOD" || doc.ID != "urn:example:apod" {
+		t.Errorf("unexpected feed: %s %s", doc.Title, doc.ID)
+	}
+
+	if doc.Updated != "2020-04-29T00:00:00Z" {
+		t.Errorf("expected: 2020-04-29T00:00:00Z, got: %s", doc.Updated)
+	}
+
+	if len(doc.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got: %d", len(doc.Entries))
+	}
+
+	entry := doc.Entries[0]
+	if entry.ID != GUID(testImages[1].Date.Time) || entry.Rights != "Jane Doe" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	var enclosure atomLink
+	for _, l := range entry.Links {
+		if l.Rel == "enclosure" {
+			enclosure = l
+		}
+	}
+
+	if enclosure.Href != testImages[1].URL || enclosure.Type != "image/jpeg" {
+		t.Errorf("unexpected enclosure: %+v", enclosure)
+	}
+
+	if doc.Entries[1].Rights != "" {
+		t.Errorf("expected no rights for public domain image, got: %s", doc.Entries[1].Rights)
+	}
+}
@@ -0,0 +1,88 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Rights      string        `xml:"dc:rights,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// WriteRSS writes images to w as an RSS 2.0 feed, newest first. Each item
+// links to its apod.nasa.gov page, has a GUID from GUID, attaches the image
+// as an enclosure, and credits APODImage.Copyright in dc:rights.
+func (f *Feed) WriteRSS(w io.Writer, images []nasa.APODImage) error {
+	d := f.withDefaults()
+	images = sorted(images)
+
+	doc := rss{
+		Version: "2.0",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         d.Title,
+			Link:          d.Link,
+			Description:   d.Description,
+			LastBuildDate: updated(images).Format(time.RFC1123Z),
+			Generator:     "nasa-go/" + nasa.Version,
+		},
+	}
+
+	for i := range images {
+		img := &images[i]
+
+		item := rssItem{
+			Title:       img.Title,
+			Link:        APODPageURL(img.Date.Time),
+			Description: d.content(img),
+			GUID:        rssGUID{Value: GUID(img.Date.Time)},
+			PubDate:     img.Date.Format(time.RFC1123Z),
+			Rights:      copyright(img),
+		}
+
+		// The image size is unknown; a zero length is the accepted convention.
+		if u := d.imageURL(img); u != "" {
+			item.Enclosure = &rssEnclosure{URL: u, Type: imageType(u)}
+		}
+
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	return writeXML(w, doc)
+}
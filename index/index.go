@@ -0,0 +1,265 @@
+// Package index is an on-disk full-text index of Astronomy Pictures Of the
+// Day, searchable by title and explanation.
+//
+//	ix, err := index.Open("apod.idx")
+//	...
+//	// Fetch and index every APOD since the last update.
+//	if _, err := ix.Update(ctx, client, nil); err != nil {
+//		...
+//	}
+//	results, err := ix.Search(`"horsehead nebula" OR barnard`, nil)
+package index
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/Oshuma/nasa"
+)
+
+// formatVersion is bumped whenever the file format changes.
+const formatVersion = 1
+
+const dateFormat = "2006-01-02"
+
+var (
+	// ErrorInvalidQuery is returned when a search query cannot be parsed.
+	ErrorInvalidQuery = errors.New("invalid query")
+
+	// ErrorUnsupportedFormat is returned when an index file was written by an
+	// incompatible version of this package.
+	ErrorUnsupportedFormat = errors.New("unsupported index format")
+)
+
+// Index is an inverted index of APODs stored in a single file. It is safe
+// for concurrent use; changes are kept in memory until Save is called.
+type Index struct {
+	path string
+
+	mu       sync.RWMutex
+	docs     map[string]*document
+	postings map[string]map[string][]int
+}
+
+// document is an indexed APOD. Title terms take positions [0, TitleLen) and
+// explanation terms follow after a gap, so phrases never span both.
+type document struct {
+	Image    nasa.APODImage
+	TitleLen int
+}
+
+// file is the on-disk representation of an Index.
+type file struct {
+	Version  int
+	Docs     map[string]*document
+	Postings map[string]map[string][]int
+}
+
+// Open opens the index stored at path. A missing file opens an empty index
+// which is created by the first Save.
+func Open(path string) (*Index, error) {
+	ix := &Index{
+		path:     path,
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string][]int),
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return ix, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data := file{}
+	if err := gob.NewDecoder(f).Decode(&data); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if data.Version != formatVersion {
+		return nil, fmt.Errorf("%s: %w: version %d", path, ErrorUnsupportedFormat, data.Version)
+	}
+
+	if data.Docs != nil {
+		ix.docs = data.Docs
+	}
+	if data.Postings != nil {
+		ix.postings = data.Postings
+	}
+
+	return ix, nil
+}
+
+// Save writes the index to its file, replacing it atomically.
+func (ix *Index) Save() error {
+	dir := filepath.Dir(ix.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+
+	ix.mu.RLock()
+	err = gob.NewEncoder(f).Encode(file{
+		Version:  formatVersion,
+		Docs:     ix.docs,
+		Postings: ix.postings,
+	})
+	ix.mu.RUnlock()
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := os.Rename(f.Name(), ix.path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// Len returns the number of indexed APODs.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Latest returns the date of the newest indexed APOD, or the zero time if
+// the index is empty.
+func (ix *Index) Latest() time.Time {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	latest := ""
+	for d := range ix.docs {
+		if d > latest {
+			latest = d
+		}
+	}
+
+	t, _ := time.Parse(dateFormat, latest)
+	return t
+}
+
+// Get returns the indexed APOD for date.
+func (ix *Index) Get(date time.Time) (nasa.APODImage, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	d, ok := ix.docs[date.Format(dateFormat)]
+	if !ok {
+		return nasa.APODImage{}, false
+	}
+	return d.Image, true
+}
+
+// Add indexes images, replacing any APOD already indexed for the same date.
+func (ix *Index) Add(images ...nasa.APODImage) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	for _, img := range images {
+		key := img.Date.Format(dateFormat)
+		ix.remove(key)
+
+		title := tokenize(img.Title)
+		explanation := tokenize(img.Explanation)
+
+		doc := &document{
+			Image:    img,
+			TitleLen: len(title),
+		}
+		ix.docs[key] = doc
+
+		for i, t := range title {
+			ix.addPosting(t, key, i)
+		}
+		for i, t := range explanation {
+			ix.addPosting(t, key, len(title)+1+i)
+		}
+	}
+}
+
+func (ix *Index) addPosting(term, key string, pos int) {
+	p, ok := ix.postings[term]
+	if !ok {
+		p = make(map[string][]int)
+		ix.postings[term] = p
+	}
+	p[key] = append(p[key], pos)
+}
+
+// remove drops the APOD indexed under key, if any.
+func (ix *Index) remove(key string) {
+	doc, ok := ix.docs[key]
+	if !ok {
+		return
+	}
+	delete(ix.docs, key)
+
+	terms := append(tokenize(doc.Image.Title), tokenize(doc.Image.Explanation)...)
+	for _, t := range terms {
+		if p, ok := ix.postings[t]; ok {
+			delete(p, key)
+			if len(p) == 0 {
+				delete(ix.postings, t)
+			}
+		}
+	}
+}
+
+// Update fetches APODs with the archive iterator, indexes them and saves the
+// index. If p or p.Start is unset it continues from the day after Latest, so
+// it can be run daily to keep the index current. It returns the number of
+// APODs indexed; those fetched before an error are saved too.
+func (ix *Index) Update(ctx context.Context, c *nasa.Client, p *nasa.APODArchiveParams) (int, error) {
+	params := nasa.APODArchiveParams{}
+	if p != nil {
+		params = *p
+	}
+	if params.Start.IsZero() {
+		if latest := ix.Latest(); !latest.IsZero() {
+			params.Start = latest.AddDate(0, 0, 1)
+		}
+	}
+
+	n := 0
+	it := c.APODArchiveContext(ctx, &params)
+	for it.Next() {
+		ix.Add(it.Image())
+		n++
+	}
+
+	if n > 0 {
+		if err := ix.Save(); err != nil {
+			return n, err
+		}
+	}
+
+	return n, it.Err()
+}
+
+// tokenize splits s into lower case terms of letters and digits.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,139 @@
+package index
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Oshuma/nasa"
+	"github.com/Oshuma/nasa/nasatest"
+)
+
+func testImage(date, title, explanation, mediaType string) nasa.APODImage {
+	d, _ := time.Parse("2006-01-02", date)
+	return nasa.APODImage{
+		Date:        nasa.Date{Time: d},
+		Title:       title,
+		Explanation: explanation,
+		MediaType:   mediaType,
+	}
+}
+
+func TestIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nasa-index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "apod.idx")
+	ix, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ix.Add(
+		testImage("2020-04-27", "The Horsehead Nebula", "Barnard 33 in Orion.", "image"),
+		testImage("2020-04-28", "Orion Launch", "A rocket launch.", "video"),
+	)
+
+	t.Run("reopen", func(t *testing.T) {
+		if err := ix.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		reopened, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if reopened.Len() != 2 {
+			t.Errorf("expected 2 APODs, got: %d", reopened.Len())
+		}
+
+		if got := reopened.Latest().Format("2006-01-02"); got != "2020-04-28" {
+			t.Errorf("expected: 2020-04-28, got: %s", got)
+		}
+
+		results, err := reopened.Search(`"horsehead nebula"`, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 1 || results[0].Image.Title != "The Horsehead Nebula" {
+			t.Errorf("unexpected results: %+v", results)
+		}
+
+		if !results[0].Image.Date.Equal(time.Date(2020, 4, 27, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected: 2020-04-27, got: %s", results[0].Image.Date)
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		ix.Add(testImage("2020-04-28", "Comet", "A comet.", "image"))
+
+		if results, _ := ix.Search("rocket", nil); len(results) != 0 {
+			t.Errorf("expected replaced APOD to be removed, got: %+v", results)
+		}
+
+		if results, _ := ix.Search("comet", nil); len(results) != 1 {
+			t.Errorf("expected 1 result, got: %d", len(results))
+		}
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	s := nasatest.NewServer()
+	defer s.Close()
+
+	dir, err := ioutil.TempDir("", "nasa-index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ix, err := Open(filepath.Join(dir, "apod.idx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start, _ := time.Parse("2006-01-02", "2020-04-27")
+	end, _ := time.Parse("2006-01-02", nasatest.FixtureAPODDate)
+	c := s.NewClient(nasa.WithAPIKey("NASA_KEY"))
+
+	n, err := ix.Update(context.Background(), c, &nasa.APODArchiveParams{Start: start, End: end})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 3 || ix.Len() != 3 {
+		t.Errorf("expected 3 APODs, got: %d (indexed %d)", ix.Len(), n)
+	}
+
+	// Continues after the latest indexed APOD.
+	n, err = ix.Update(context.Background(), c, &nasa.APODArchiveParams{End: end})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 0 {
+		t.Errorf("expected no new APODs, got: %d", n)
+	}
+
+	reopened, err := Open(filepath.Join(dir, "apod.idx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := reopened.Search("galaxy", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 || results[0].Image.Title != "Fixture Galaxy" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
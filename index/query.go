@@ -0,0 +1,332 @@
+package index
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// A query is made of terms and "quoted phrases" combined with AND, OR and
+// NOT (or a leading -), grouped with parentheses. Terms next to each other
+// are ANDed; AND binds tighter than OR. Operators must be upper case.
+//
+//	horsehead nebula
+//	"horsehead nebula" OR (barnard AND 33)
+//	galaxy -andromeda
+type node interface {
+	// eval returns the score of each matching document.
+	eval(ix *Index) map[string]float64
+}
+
+// phrase matches documents containing its terms in order. A single term is
+// a phrase of one.
+type phrase []string
+
+type and []node
+
+type or []node
+
+type not struct {
+	n node
+}
+
+// titleWeight is how much more a match in the title counts than one in the explanation.
+const titleWeight = 3
+
+func (p phrase) eval(ix *Index) map[string]float64 {
+	scores := make(map[string]float64)
+
+	first, ok := ix.postings[p[0]]
+	if !ok {
+		return scores
+	}
+
+	for key, positions := range first {
+		doc := ix.docs[key]
+
+		tf := 0.0
+		for _, pos := range positions {
+			if !ix.phraseAt(p, key, pos) {
+				continue
+			}
+			if pos < doc.TitleLen {
+				tf += titleWeight
+			} else {
+				tf++
+			}
+		}
+
+		if tf > 0 {
+			scores[key] = tf
+		}
+	}
+
+	// Rare phrases score higher; repeated matches help, with diminishing returns.
+	idf := math.Log(1 + float64(len(ix.docs))/float64(len(scores)+1))
+	for key, tf := range scores {
+		scores[key] = (1 + math.Log(tf)) * idf
+	}
+
+	return scores
+}
+
+// phraseAt reports whether the rest of p follows the first term at pos in key.
+func (ix *Index) phraseAt(p phrase, key string, pos int) bool {
+	for i, t := range p[1:] {
+		if !containsInt(ix.postings[t][key], pos+1+i) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsInt(s []int, n int) bool {
+	for _, v := range s {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+func (a and) eval(ix *Index) map[string]float64 {
+	var scores map[string]float64
+	for _, n := range a {
+		s := n.eval(ix)
+		if scores == nil {
+			scores = s
+			continue
+		}
+		for key, score := range scores {
+			if other, ok := s[key]; ok {
+				scores[key] = score + other
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+	return scores
+}
+
+func (o or) eval(ix *Index) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, n := range o {
+		for key, score := range n.eval(ix) {
+			scores[key] += score
+		}
+	}
+	return scores
+}
+
+func (n not) eval(ix *Index) map[string]float64 {
+	excluded := n.n.eval(ix)
+	scores := make(map[string]float64)
+	for key := range ix.docs {
+		if _, ok := excluded[key]; !ok {
+			scores[key] = 0
+		}
+	}
+	return scores
+}
+
+// all matches every document; it is the query for an empty string.
+type all struct{}
+
+func (all) eval(ix *Index) map[string]float64 {
+	scores := make(map[string]float64, len(ix.docs))
+	for key := range ix.docs {
+		scores[key] = 0
+	}
+	return scores
+}
+
+// token kinds produced by lex.
+const (
+	tokenWord = iota
+	tokenPhrase
+	tokenOpen
+	tokenClose
+	tokenNot
+)
+
+type token struct {
+	kind int
+	text string
+}
+
+func lex(q string) ([]token, error) {
+	tokens := []token{}
+
+	for i := 0; i < len(q); {
+		switch c := q[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose})
+			i++
+		case c == '"':
+			end := strings.IndexByte(q[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated phrase", ErrorInvalidQuery)
+			}
+			tokens = append(tokens, token{kind: tokenPhrase, text: q[i+1 : i+1+end]})
+			i += end + 2
+		case c == '-' && i+1 < len(q) && q[i+1] != ' ':
+			tokens = append(tokens, token{kind: tokenNot})
+			i++
+		default:
+			end := strings.IndexAny(q[i:], " \t\n\r()\"")
+			if end < 0 {
+				end = len(q) - i
+			}
+			word := q[i : i+end]
+			if word == "NOT" {
+				tokens = append(tokens, token{kind: tokenNot})
+			} else {
+				tokens = append(tokens, token{kind: tokenWord, text: word})
+			}
+			i += end
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses q into a query tree.
+func parse(q string) (node, error) {
+	tokens, err := lex(q)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return all{}, nil
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected )", ErrorInvalidQuery)
+	}
+	if n == nil {
+		return all{}, nil
+	}
+	return n, nil
+}
+
+func (p *parser) peek() *token {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *parser) isWord(w string) bool {
+	t := p.peek()
+	return t != nil && t.kind == tokenWord && t.text == w
+}
+
+func (p *parser) or() (node, error) {
+	nodes := or{}
+	for {
+		n, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+
+		if !p.isWord("OR") {
+			break
+		}
+		p.pos++
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *parser) and() (node, error) {
+	nodes := and{}
+	for {
+		t := p.peek()
+		if t == nil || t.kind == tokenClose || p.isWord("OR") {
+			break
+		}
+		if p.isWord("AND") {
+			p.pos++
+			continue
+		}
+
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *parser) unary() (node, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("%w: missing term", ErrorInvalidQuery)
+	}
+
+	switch t.kind {
+	case tokenNot:
+		p.pos++
+		n, err := p.unary()
+		if err != nil || n == nil {
+			return nil, err
+		}
+		return not{n: n}, nil
+	case tokenOpen:
+		p.pos++
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t == nil || t.kind != tokenClose {
+			return nil, fmt.Errorf("%w: missing )", ErrorInvalidQuery)
+		}
+		p.pos++
+		return n, nil
+	case tokenClose:
+		return nil, fmt.Errorf("%w: unexpected )", ErrorInvalidQuery)
+	}
+
+	p.pos++
+
+	// Words with punctuation, like NGC-2023, are phrases of their parts.
+	terms := tokenize(t.text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	return phrase(terms), nil
+}
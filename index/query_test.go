@@ -0,0 +1,68 @@
+package index
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	ix := &Index{
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string][]int),
+	}
+	ix.Add(
+		testImage("2020-01-01", "The Horsehead Nebula", "Barnard 33 is a dark nebula in Orion.", "image"),
+		testImage("2020-01-02", "Orion Nebula", "Near the horsehead, a bright nebula in Orion.", "image"),
+		testImage("2020-01-03", "Andromeda Galaxy", "Our nearest large galaxy, M31.", "image"),
+		testImage("2020-01-04", "Galaxy Flyby", "A video of the galaxy NGC-1300.", "video"),
+	)
+
+	tests := []struct {
+		query    string
+		opts     *SearchOptions
+		expected string
+	}{
+		{"horsehead", nil, "2020-01-01 2020-01-02"},
+		{`"horsehead nebula"`, nil, "2020-01-01"},
+		{"horsehead nebula", nil, "2020-01-01 2020-01-02"},
+		{"nebula AND barnard", nil, "2020-01-01"},
+		{"barnard OR andromeda", nil, "2020-01-03 2020-01-01"},
+		{"galaxy -andromeda", nil, "2020-01-04"},
+		{"galaxy NOT andromeda", nil, "2020-01-04"},
+		{"(barnard OR m31) AND dark", nil, "2020-01-01"},
+		{"ngc-1300", nil, "2020-01-04"},
+		{"nebula orion", nil, "2020-01-02 2020-01-01"},
+		{"galaxy", &SearchOptions{MediaType: "image"}, "2020-01-03"},
+		{"", &SearchOptions{From: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), To: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)}, "2020-01-03 2020-01-02"},
+		{"horsehead", &SearchOptions{Limit: 1}, "2020-01-01"},
+		{"pulsar", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := ix.Search(tt.query, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dates := []string{}
+			for _, r := range results {
+				dates = append(dates, r.Image.Date.Format("2006-01-02"))
+			}
+
+			if got := strings.Join(dates, " "); got != tt.expected {
+				t.Errorf("expected: %s, got: %s", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, q := range []string{`"horsehead`, "(nebula", "nebula)"} {
+			if _, err := ix.Search(q, nil); !errors.Is(err, ErrorInvalidQuery) {
+				t.Errorf("%s: expected ErrorInvalidQuery, got: %v", q, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package index
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+// SearchOptions filters and limits search results.
+type SearchOptions struct {
+	// From and To limit results to APODs between the dates, inclusive.
+	From time.Time
+	To   time.Time
+
+	// MediaType limits results to APODs of the media type, such as
+	// nasa.APODMediaImage or nasa.APODMediaVideo.
+	MediaType string
+
+	// Limit is the maximum number of results; zero means no limit.
+	Limit int
+}
+
+// Result is a matching APOD.
+type Result struct {
+	Image nasa.APODImage
+
+	// Score ranks the result; higher is more relevant.
+	Score float64
+}
+
+// Search returns the APODs matching query, most relevant first, with ties
+// broken by newest first. Matches in the title rank higher than matches in
+// the explanation. An empty query matches every APOD.
+func (ix *Index) Search(query string, opts *SearchOptions) ([]Result, error) {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+
+	n, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	from, to := "", ""
+	if !opts.From.IsZero() {
+		from = opts.From.Format(dateFormat)
+	}
+	if !opts.To.IsZero() {
+		to = opts.To.Format(dateFormat)
+	}
+
+	results := []Result{}
+	for key, score := range n.eval(ix) {
+		if from != "" && key < from || to != "" && key > to {
+			continue
+		}
+
+		doc := ix.docs[key]
+		if opts.MediaType != "" && doc.Image.MediaType != opts.MediaType {
+			continue
+		}
+
+		results = append(results, Result{Image: doc.Image, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Image.Date.After(results[j].Image.Date.Time)
+	})
+
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	return results, nil
+}
@@ -1,14 +1,16 @@
 package nasa
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 )
 
 const (
-	marsPhotosAPIURL          = "https://api.nasa.gov/mars-photos/api/v1/rovers/%s/photos"
-	marsLatestPhotosAPIURL    = "https://api.nasa.gov/mars-photos/api/v1/rovers/%s/latest_photos"
-	marsPhotosManifestsAPIURL = "https://api.nasa.gov/mars-photos/api/v1/manifests/%s"
+	marsPhotosPath          = "%s/rovers/%s/photos"
+	marsLatestPhotosPath    = "%s/rovers/%s/latest_photos"
+	marsPhotosManifestsPath = "%s/manifests/%s"
 )
 
 // RoverPhoto represents a single photo from a rover camera.
@@ -47,6 +49,21 @@ type RoverPhotos struct {
 
 // MarsRoverPhotos returns photos for the given params and Rover.
 func MarsRoverPhotos(p ParamEncoder, rover Rover) (RoverPhotos, error) {
+	return DefaultClient.MarsRoverPhotos(p, rover)
+}
+
+// MarsRoverPhotosContext is like MarsRoverPhotos but uses the given context.
+func MarsRoverPhotosContext(ctx context.Context, p ParamEncoder, rover Rover) (RoverPhotos, error) {
+	return DefaultClient.MarsRoverPhotosContext(ctx, p, rover)
+}
+
+// MarsRoverPhotos returns photos for the given params and Rover.
+func (c *Client) MarsRoverPhotos(p ParamEncoder, rover Rover) (RoverPhotos, error) {
+	return c.MarsRoverPhotosContext(context.Background(), p, rover)
+}
+
+// MarsRoverPhotosContext is like MarsRoverPhotos but uses the given context.
+func (c *Client) MarsRoverPhotosContext(ctx context.Context, p ParamEncoder, rover Rover) (RoverPhotos, error) {
 	params, ok := p.(*MarsPhotosParams)
 	if !ok {
 		return RoverPhotos{}, ErrorParamsMismatch
@@ -54,13 +71,13 @@ func MarsRoverPhotos(p ParamEncoder, rover Rover) (RoverPhotos, error) {
 
 	camera := params.Camera
 	if camera.Slug != "" {
-		if !hasCamera(rover, camera) {
+		if !rover.HasCamera(camera) {
 			return RoverPhotos{}, &ErrorRoverCameraMissing{rover, camera}
 		}
 	}
 
-	url := fmt.Sprintf(marsPhotosAPIURL, rover.Slug)
-	content, err := getContent(url, p)
+	url := fmt.Sprintf(marsPhotosPath, c.marsURL, rover.Slug)
+	content, err := c.getContent(ctx, EndpointMarsPhotos, url, p)
 	if err != nil {
 		return RoverPhotos{}, err
 	}
@@ -80,14 +97,60 @@ func MarsRoverPhotos(p ParamEncoder, rover Rover) (RoverPhotos, error) {
 	return photos, nil
 }
 
+// DownloadRoverPhoto writes the image of photo to w.
+func DownloadRoverPhoto(photo *RoverPhoto, w io.Writer) (int64, error) {
+	return DefaultClient.DownloadRoverPhoto(photo, w)
+}
+
+// DownloadRoverPhotoContext is like DownloadRoverPhoto but uses the given context.
+func DownloadRoverPhotoContext(ctx context.Context, photo *RoverPhoto, w io.Writer) (int64, error) {
+	return DefaultClient.DownloadRoverPhotoContext(ctx, photo, w)
+}
+
+// DownloadRoverPhoto writes the image of photo to w.
+func (c *Client) DownloadRoverPhoto(photo *RoverPhoto, w io.Writer) (int64, error) {
+	return c.DownloadRoverPhotoContext(context.Background(), photo, w)
+}
+
+// DownloadRoverPhotoContext is like DownloadRoverPhoto but uses the given context.
+func (c *Client) DownloadRoverPhotoContext(ctx context.Context, photo *RoverPhoto, w io.Writer) (int64, error) {
+	req, err := c.newRequest(ctx, photo.Image)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.open(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return io.Copy(w, resp.Body)
+}
+
 type latestPhotosResponse struct {
 	Photos []*RoverPhoto `json:"latest_photos"`
 }
 
 // MarsRoverPhotosLatest returns the most recent Sol for which photos exist.
 func MarsRoverPhotosLatest(p ParamEncoder, rover Rover) ([]*RoverPhoto, error) {
-	url := fmt.Sprintf(marsLatestPhotosAPIURL, rover.Slug)
-	content, err := getContent(url, p)
+	return DefaultClient.MarsRoverPhotosLatest(p, rover)
+}
+
+// MarsRoverPhotosLatestContext is like MarsRoverPhotosLatest but uses the given context.
+func MarsRoverPhotosLatestContext(ctx context.Context, p ParamEncoder, rover Rover) ([]*RoverPhoto, error) {
+	return DefaultClient.MarsRoverPhotosLatestContext(ctx, p, rover)
+}
+
+// MarsRoverPhotosLatest returns the most recent Sol for which photos exist.
+func (c *Client) MarsRoverPhotosLatest(p ParamEncoder, rover Rover) ([]*RoverPhoto, error) {
+	return c.MarsRoverPhotosLatestContext(context.Background(), p, rover)
+}
+
+// MarsRoverPhotosLatestContext is like MarsRoverPhotosLatest but uses the given context.
+func (c *Client) MarsRoverPhotosLatestContext(ctx context.Context, p ParamEncoder, rover Rover) ([]*RoverPhoto, error) {
+	url := fmt.Sprintf(marsLatestPhotosPath, c.marsURL, rover.Slug)
+	content, err := c.getContent(ctx, EndpointMarsLatestPhotos, url, p)
 	if err != nil {
 		return []*RoverPhoto{}, err
 	}
@@ -127,8 +190,23 @@ type manifestResponse struct {
 
 // MarsMissionManifest returns the rover mission details.
 func MarsMissionManifest(p ParamEncoder, rover Rover) (MissionManifest, error) {
-	url := fmt.Sprintf(marsPhotosManifestsAPIURL, rover.Slug)
-	content, err := getContent(url, p)
+	return DefaultClient.MarsMissionManifest(p, rover)
+}
+
+// MarsMissionManifestContext is like MarsMissionManifest but uses the given context.
+func MarsMissionManifestContext(ctx context.Context, p ParamEncoder, rover Rover) (MissionManifest, error) {
+	return DefaultClient.MarsMissionManifestContext(ctx, p, rover)
+}
+
+// MarsMissionManifest returns the rover mission details.
+func (c *Client) MarsMissionManifest(p ParamEncoder, rover Rover) (MissionManifest, error) {
+	return c.MarsMissionManifestContext(context.Background(), p, rover)
+}
+
+// MarsMissionManifestContext is like MarsMissionManifest but uses the given context.
+func (c *Client) MarsMissionManifestContext(ctx context.Context, p ParamEncoder, rover Rover) (MissionManifest, error) {
+	url := fmt.Sprintf(marsPhotosManifestsPath, c.marsURL, rover.Slug)
+	content, err := c.getContent(ctx, EndpointMarsManifest, url, p)
 	if err != nil {
 		return MissionManifest{}, err
 	}
@@ -142,9 +220,10 @@ func MarsMissionManifest(p ParamEncoder, rover Rover) (MissionManifest, error) {
 	return r.Manifest, nil
 }
 
-func hasCamera(rover Rover, camera RoverCamera) bool {
-	for _, c := range rover.Cameras {
-		if c == camera {
+// HasCamera reports whether the rover has the camera, matched by Slug.
+func (r Rover) HasCamera(camera RoverCamera) bool {
+	for _, c := range r.Cameras {
+		if c.Slug == camera.Slug {
 			return true
 		}
 	}
@@ -0,0 +1,205 @@
+package nasa
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultMarsPrefetch is the default MarsPhotosIteratorParams.Prefetch.
+const DefaultMarsPrefetch = 2
+
+// MarsPhotosIteratorParams configures a RoverPhotoIterator.
+type MarsPhotosIteratorParams struct {
+	APIKey string
+
+	// Sol is the first sol to walk, unless EarthDate is set.
+	Sol int
+
+	// EndSol is the last sol to walk. It defaults to Sol.
+	EndSol int
+
+	// EarthDate walks the photos of that day rather than of sols.
+	EarthDate time.Time
+
+	// Camera limits the photos to one camera.
+	Camera RoverCamera
+
+	// Prefetch is how many pages are requested ahead at once. It defaults
+	// to DefaultMarsPrefetch; 1 fetches one page at a time.
+	Prefetch int
+}
+
+// RoverPhotoIterator walks every page of rover photos for a sol, a range of
+// sols or an earth date. A sol is done once a page comes back empty.
+//
+//	it := client.MarsRoverPhotosIterator(&nasa.MarsPhotosIteratorParams{Sol: 1000, EndSol: 1010}, nasa.RoverCuriosity)
+//	defer it.Close()
+//	for it.Next() {
+//		photo := it.Photo()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type RoverPhotoIterator struct {
+	c      *Client
+	ctx    context.Context
+	cancel context.CancelFunc
+	p      MarsPhotosIteratorParams
+	rover  Rover
+
+	// The next page to request.
+	sol  int
+	page int
+	done bool
+
+	pending []*marsPage
+	buf     []*RoverPhoto
+	cur     *RoverPhoto
+	err     error
+	closed  bool
+}
+
+// marsPage is a page request which may still be in flight.
+type marsPage struct {
+	sol    int
+	cancel context.CancelFunc
+	ready  chan struct{}
+	photos []*RoverPhoto
+	err    error
+}
+
+// MarsRoverPhotosIterator returns an iterator over the photos of rover.
+func MarsRoverPhotosIterator(p *MarsPhotosIteratorParams, rover Rover) *RoverPhotoIterator {
+	return DefaultClient.MarsRoverPhotosIterator(p, rover)
+}
+
+// MarsRoverPhotosIteratorContext is like MarsRoverPhotosIterator but uses the given context.
+func MarsRoverPhotosIteratorContext(ctx context.Context, p *MarsPhotosIteratorParams, rover Rover) *RoverPhotoIterator {
+	return DefaultClient.MarsRoverPhotosIteratorContext(ctx, p, rover)
+}
+
+// MarsRoverPhotosIterator returns an iterator over the photos of rover.
+func (c *Client) MarsRoverPhotosIterator(p *MarsPhotosIteratorParams, rover Rover) *RoverPhotoIterator {
+	return c.MarsRoverPhotosIteratorContext(context.Background(), p, rover)
+}
+
+// MarsRoverPhotosIteratorContext is like MarsRoverPhotosIterator but uses
+// the given context. Cancelling it stops the iterator with the context error.
+// A nil p stops the iterator with ErrorParamsMismatch.
+func (c *Client) MarsRoverPhotosIteratorContext(ctx context.Context, p *MarsPhotosIteratorParams, rover Rover) *RoverPhotoIterator {
+	it := &RoverPhotoIterator{c: c, rover: rover, page: 1}
+	it.ctx, it.cancel = context.WithCancel(ctx)
+
+	if p == nil {
+		it.err = ErrorParamsMismatch
+		it.cancel()
+		return it
+	}
+	it.p, it.sol = *p, p.Sol
+
+	if !it.p.EarthDate.IsZero() || it.p.EndSol < it.p.Sol {
+		it.p.EndSol = it.p.Sol
+	}
+	if it.p.Prefetch <= 0 {
+		it.p.Prefetch = DefaultMarsPrefetch
+	}
+
+	return it
+}
+
+// Next advances to the next photo, fetching more pages if needed. It
+// returns false when every page was walked, an error occurred or the
+// iterator was closed.
+func (it *RoverPhotoIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.err != nil || it.closed {
+			return false
+		}
+
+		it.request()
+		if len(it.pending) == 0 {
+			it.cancel()
+			return false
+		}
+		it.receive()
+	}
+
+	it.cur = it.buf[0]
+	it.buf = it.buf[1:]
+
+	return true
+}
+
+// request starts page requests until Prefetch are in flight.
+func (it *RoverPhotoIterator) request() {
+	for !it.done && len(it.pending) < it.p.Prefetch {
+		ctx, cancel := context.WithCancel(it.ctx)
+		page := &marsPage{sol: it.sol, cancel: cancel, ready: make(chan struct{})}
+		params := &MarsPhotosParams{
+			APIKey:    it.p.APIKey,
+			Sol:       it.sol,
+			EarthDate: it.p.EarthDate,
+			Camera:    it.p.Camera,
+			Page:      it.page,
+		}
+
+		go func() {
+			defer close(page.ready)
+			resp, err := it.c.MarsRoverPhotosContext(ctx, params, it.rover)
+			page.photos, page.err = resp.Photos, err
+		}()
+
+		it.pending = append(it.pending, page)
+		it.page++
+	}
+}
+
+// receive waits for the oldest page request and handles its photos.
+func (it *RoverPhotoIterator) receive() {
+	page := it.pending[0]
+	it.pending = it.pending[1:]
+	<-page.ready
+	page.cancel()
+
+	if page.err != nil {
+		it.err = page.err
+		it.cancel()
+		return
+	}
+
+	if len(page.photos) > 0 {
+		it.buf = page.photos
+		return
+	}
+
+	// The sol is done: drop the requests past its end and move on.
+	for len(it.pending) > 0 && it.pending[0].sol == page.sol {
+		it.pending[0].cancel()
+		it.pending = it.pending[1:]
+	}
+	if it.sol == page.sol {
+		it.sol++
+		it.page = 1
+		it.done = it.sol > it.p.EndSol
+	}
+}
+
+// Photo returns the current photo.
+func (it *RoverPhotoIterator) Photo() *RoverPhoto {
+	return it.cur
+}
+
+// Err returns the error which stopped the iterator, if any.
+func (it *RoverPhotoIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and abandons the requests in flight. It does not
+// set Err.
+func (it *RoverPhotoIterator) Close() {
+	it.closed = true
+	it.buf = nil
+	it.pending = nil
+	it.cancel()
+}
@@ -1,23 +1,201 @@
 package nasa
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestHasCamera(t *testing.T) {
 	t.Run("has camera", func(t *testing.T) {
 		r := RoverCuriosity
 		c := RoverCameraFHAZ
-		if !hasCamera(r, c) {
+		if !r.HasCamera(c) {
 			t.Errorf("rover %s should have camera %s", r.Name, c.Name)
 		}
 	})
 
+	t.Run("matches by slug", func(t *testing.T) {
+		r := RoverCuriosity
+		c := RoverCamera{Slug: RoverCameraNAVCAM.Slug}
+		if !r.HasCamera(c) {
+			t.Errorf("rover %s should have camera %s", r.Name, c.Slug)
+		}
+	})
+
 	t.Run("does not have camera", func(t *testing.T) {
 		r := RoverCuriosity
 		c := RoverCameraPANCAM
-		if hasCamera(r, c) {
+		if r.HasCamera(c) {
 			t.Errorf("rover %s should not have camera %s", r.Name, c.Name)
 		}
 	})
 }
+
+func TestRoverPhotoIterator(t *testing.T) {
+	// Photos per sol; 1002 has none.
+	sols := map[int]int{1000: 30, 1001: 25, 1003: 3}
+
+	var (
+		mu       sync.Mutex
+		requests int // for the earth date
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		sol, _ := strconv.Atoi(q.Get("sol"))
+		if q.Get("earth_date") == "2015-05-30" {
+			sol = 1000
+
+			mu.Lock()
+			requests++
+			mu.Unlock()
+		}
+		if sol == 1666 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		page, _ := strconv.Atoi(q.Get("page"))
+
+		resp := RoverPhotos{Photos: []*RoverPhoto{}}
+		for i := (page - 1) * 25; i < page*25 && i < sols[sol]; i++ {
+			resp.Photos = append(resp.Photos, &RoverPhoto{ID: sol*100 + i, Sol: sol})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithMarsURL(ts.URL), WithAPIKey("NASA_KEY"))
+
+	collect := func(it *RoverPhotoIterator) []int {
+		ids := []int{}
+		for it.Next() {
+			ids = append(ids, it.Photo().ID)
+		}
+		return ids
+	}
+
+	t.Run("sol range", func(t *testing.T) {
+		for _, prefetch := range []int{1, 3} {
+			it := c.MarsRoverPhotosIterator(&MarsPhotosIteratorParams{Sol: 1000, EndSol: 1003, Prefetch: prefetch}, RoverCuriosity)
+			ids := collect(it)
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(ids) != 58 {
+				t.Fatalf("expected: 58 photos, got: %d", len(ids))
+			}
+			for i := 1; i < len(ids); i++ {
+				if ids[i] <= ids[i-1] {
+					t.Fatalf("photos out of order: %v", ids)
+				}
+			}
+		}
+	})
+
+	t.Run("earth date", func(t *testing.T) {
+		it := c.MarsRoverPhotosIterator(&MarsPhotosIteratorParams{
+			EarthDate: time.Date(2015, 5, 30, 0, 0, 0, 0, time.UTC),
+			Prefetch:  1,
+		}, RoverCuriosity)
+		if ids := collect(it); len(ids) != 30 || it.Err() != nil {
+			t.Errorf("expected: 30 photos, got: %d, %v", len(ids), it.Err())
+		}
+
+		// Two pages of photos and the empty one.
+		mu.Lock()
+		defer mu.Unlock()
+		if requests != 3 {
+			t.Errorf("expected: 3 requests, got: %d", requests)
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		it := c.MarsRoverPhotosIterator(&MarsPhotosIteratorParams{Sol: 1000, EndSol: 1003}, RoverCuriosity)
+		for i := 0; i < 5; i++ {
+			if !it.Next() {
+				t.Fatal("expected a photo")
+			}
+		}
+
+		it.Close()
+		if it.Next() || it.Err() != nil {
+			t.Errorf("expected the iterator stopped without error: %v", it.Err())
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		it := c.MarsRoverPhotosIterator(&MarsPhotosIteratorParams{Sol: 1665, EndSol: 1667}, RoverCuriosity)
+		defer it.Close()
+
+		collect(it)
+		apiErr := &APIError{}
+		if !errors.As(it.Err(), &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+			t.Errorf("expected a 500 APIError, got: %v", it.Err())
+		}
+	})
+
+	t.Run("nil params", func(t *testing.T) {
+		it := c.MarsRoverPhotosIterator(nil, RoverCuriosity)
+		if it.Next() || it.Err() != ErrorParamsMismatch {
+			t.Errorf("wrong error returned: %v", it.Err())
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		it := c.MarsRoverPhotosIteratorContext(ctx, &MarsPhotosIteratorParams{Sol: 1000}, RoverCuriosity)
+		if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+			t.Errorf("expected context.Canceled, got: %v", it.Err())
+		}
+	})
+}
+
+func TestRoverPhotoIteratorDropped(t *testing.T) {
+	released := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		resp := RoverPhotos{Photos: []*RoverPhoto{}}
+
+		switch q.Get("sol") + "/" + q.Get("page") {
+		case "1000/1":
+			resp.Photos = append(resp.Photos, &RoverPhoto{ID: 1})
+		case "1000/3":
+			// Past the end of the sol: held until the iterator drops it.
+			<-r.Context().Done()
+			close(released)
+			return
+		case "1001/1":
+			select {
+			case <-released:
+			case <-time.After(5 * time.Second):
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			resp.Photos = append(resp.Photos, &RoverPhoto{ID: 2})
+		}
+
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithMarsURL(ts.URL), WithAPIKey("NASA_KEY"))
+	it := c.MarsRoverPhotosIterator(&MarsPhotosIteratorParams{Sol: 1000, EndSol: 1001, Prefetch: 3}, RoverCuriosity)
+	defer it.Close()
+
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 2 || it.Err() != nil {
+		t.Errorf("expected: 2 photos, got: %d, %v", n, it.Err())
+	}
+}
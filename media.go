@@ -1,6 +1,7 @@
 package nasa
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,11 +10,11 @@ import (
 )
 
 const (
-	mediaAPIURL    = "https://images-api.nasa.gov/search"
-	assetAPIURL    = "https://images-api.nasa.gov/asset/%s"
-	metadataAPIURL = "https://images-api.nasa.gov/metadata/%s"
-	captionsAPIURL = "https://images-api.nasa.gov/captions/%s"
-	albumAPIURL    = "https://images-api.nasa.gov/album/%s"
+	mediaSearchPath   = "%s/search"
+	mediaAssetPath    = "%s/asset/%s"
+	mediaMetadataPath = "%s/metadata/%s"
+	mediaCaptionsPath = "%s/captions/%s"
+	mediaAlbumPath    = "%s/album/%s"
 )
 
 // Media represents a media search response.
@@ -59,7 +60,23 @@ type mediaResponse struct {
 
 // MediaSearch searches the NASA Image and Video Library.
 func MediaSearch(p ParamEncoder) (Media, error) {
-	content, err := getContent(mediaAPIURL, p)
+	return DefaultClient.MediaSearch(p)
+}
+
+// MediaSearchContext is like MediaSearch but uses the given context.
+func MediaSearchContext(ctx context.Context, p ParamEncoder) (Media, error) {
+	return DefaultClient.MediaSearchContext(ctx, p)
+}
+
+// MediaSearch searches the NASA Image and Video Library.
+func (c *Client) MediaSearch(p ParamEncoder) (Media, error) {
+	return c.MediaSearchContext(context.Background(), p)
+}
+
+// MediaSearchContext is like MediaSearch but uses the given context.
+func (c *Client) MediaSearchContext(ctx context.Context, p ParamEncoder) (Media, error) {
+	url := fmt.Sprintf(mediaSearchPath, c.mediaURL)
+	content, err := c.getContent(ctx, EndpointMediaSearch, url, p)
 	if err != nil {
 		return Media{}, err
 	}
@@ -88,8 +105,23 @@ type mediaAssetResponse struct {
 
 // GetMediaAssets gets the media assets for the given nasaID.
 func GetMediaAssets(nasaID string) (MediaAssets, error) {
-	url := fmt.Sprintf(assetAPIURL, nasaID)
-	content, err := getContent(url, nil)
+	return DefaultClient.GetMediaAssets(nasaID)
+}
+
+// GetMediaAssetsContext is like GetMediaAssets but uses the given context.
+func GetMediaAssetsContext(ctx context.Context, nasaID string) (MediaAssets, error) {
+	return DefaultClient.GetMediaAssetsContext(ctx, nasaID)
+}
+
+// GetMediaAssets gets the media assets for the given nasaID.
+func (c *Client) GetMediaAssets(nasaID string) (MediaAssets, error) {
+	return c.GetMediaAssetsContext(context.Background(), nasaID)
+}
+
+// GetMediaAssetsContext is like GetMediaAssets but uses the given context.
+func (c *Client) GetMediaAssetsContext(ctx context.Context, nasaID string) (MediaAssets, error) {
+	url := fmt.Sprintf(mediaAssetPath, c.mediaURL, nasaID)
+	content, err := c.getContent(ctx, EndpointMediaAsset, url, nil)
 	if err != nil {
 		return MediaAssets{}, err
 	}
@@ -176,8 +208,23 @@ type MediaMetadata struct {
 
 // GetMediaMetadata gets the metadata for media with nasaID.
 func GetMediaMetadata(nasaID string) (MediaMetadata, error) {
-	url := fmt.Sprintf(metadataAPIURL, nasaID)
-	content, err := getContent(url, nil)
+	return DefaultClient.GetMediaMetadata(nasaID)
+}
+
+// GetMediaMetadataContext is like GetMediaMetadata but uses the given context.
+func GetMediaMetadataContext(ctx context.Context, nasaID string) (MediaMetadata, error) {
+	return DefaultClient.GetMediaMetadataContext(ctx, nasaID)
+}
+
+// GetMediaMetadata gets the metadata for media with nasaID.
+func (c *Client) GetMediaMetadata(nasaID string) (MediaMetadata, error) {
+	return c.GetMediaMetadataContext(context.Background(), nasaID)
+}
+
+// GetMediaMetadataContext is like GetMediaMetadata but uses the given context.
+func (c *Client) GetMediaMetadataContext(ctx context.Context, nasaID string) (MediaMetadata, error) {
+	url := fmt.Sprintf(mediaMetadataPath, c.mediaURL, nasaID)
+	content, err := c.getContent(ctx, EndpointMediaMetadata, url, nil)
 	if err != nil {
 		return MediaMetadata{}, err
 	}
@@ -194,7 +241,7 @@ func GetMediaMetadata(nasaID string) (MediaMetadata, error) {
 		return MediaMetadata{}, ErrorNoMetadata
 	}
 
-	content, err = getContent(resp.Location, nil)
+	content, err = c.getContent(ctx, EndpointMediaMetadata, resp.Location, nil)
 	if err != nil {
 		return MediaMetadata{}, err
 	}
@@ -209,10 +256,25 @@ func GetMediaMetadata(nasaID string) (MediaMetadata, error) {
 }
 
 // GetMediaCaptions returns the captions for the given nasaID.
-// TODO: Maybe parse the captions in later versions.
 func GetMediaCaptions(nasaID string) (string, error) {
-	url := fmt.Sprintf(captionsAPIURL, nasaID)
-	content, err := getContent(url, nil)
+	return DefaultClient.GetMediaCaptions(nasaID)
+}
+
+// GetMediaCaptionsContext is like GetMediaCaptions but uses the given context.
+func GetMediaCaptionsContext(ctx context.Context, nasaID string) (string, error) {
+	return DefaultClient.GetMediaCaptionsContext(ctx, nasaID)
+}
+
+// GetMediaCaptions returns the captions for the given nasaID.
+// TODO: Maybe parse the captions in later versions.
+func (c *Client) GetMediaCaptions(nasaID string) (string, error) {
+	return c.GetMediaCaptionsContext(context.Background(), nasaID)
+}
+
+// GetMediaCaptionsContext is like GetMediaCaptions but uses the given context.
+func (c *Client) GetMediaCaptionsContext(ctx context.Context, nasaID string) (string, error) {
+	url := fmt.Sprintf(mediaCaptionsPath, c.mediaURL, nasaID)
+	content, err := c.getContent(ctx, EndpointMediaCaptions, url, nil)
 	if err != nil {
 		return "", err
 	}
@@ -232,13 +294,13 @@ func GetMediaCaptions(nasaID string) (string, error) {
 	type captionLocation struct {
 		Location string `json:"location"`
 	}
-	c := captionLocation{}
-	err = json.Unmarshal(content, &c)
+	loc := captionLocation{}
+	err = json.Unmarshal(content, &loc)
 	if err != nil {
 		return "", err
 	}
 
-	captions, err := getContent(c.Location, nil)
+	captions, err := c.getContent(ctx, EndpointMediaCaptions, loc.Location, nil)
 	if err != nil {
 		return "", err
 	}
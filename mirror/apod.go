@@ -0,0 +1,94 @@
+package mirror
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+func apodPath(img *nasa.APODImage, ext string) string {
+	return "apod/" + img.Date.Format("2006/01/2006-01-02") + ext
+}
+
+// apodImageURL returns the URL of the image to mirror for img, or "" for videos.
+func (m *Mirror) apodImageURL(img *nasa.APODImage) string {
+	if img.IsVideo() {
+		return ""
+	}
+	if m.cfg.APOD.SD || img.HDURL == "" {
+		return img.URL
+	}
+	return img.HDURL
+}
+
+func (m *Mirror) syncAPOD(ctx context.Context) error {
+	cfg := m.cfg.APOD
+
+	start := cfg.Start.Time
+	if start.IsZero() {
+		start = nasa.APODFirstDate
+	}
+
+	// Start from the first day not mirrored for good.
+	for ; !start.After(time.Now()); start = start.AddDate(0, 0, 1) {
+		if nasa.IsAPODGapDay(start) {
+			continue
+		}
+		n, done := m.apodDone(start)
+		if !done {
+			break
+		}
+		m.stats.Skipped += n
+	}
+
+	it := m.c.APODArchiveContext(ctx, &nasa.APODArchiveParams{
+		Start:  start,
+		End:    cfg.End.Time,
+		Thumbs: true,
+	})
+	for it.Next() {
+		img := it.Image()
+
+		if err := m.writeJSON(apodPath(&img, ".json"), m.isFinal(img.Date.Time), img); err != nil {
+			return err
+		}
+
+		if u := m.apodImageURL(&img); cfg.Images && u != "" {
+			err := m.download(apodPath(&img, urlExt(u, ".jpg")), u, func(w io.Writer) error {
+				_, err := m.c.DownloadAPODContext(ctx, &img, w, &nasa.APODDownloadOptions{SD: cfg.SD})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return it.Err()
+}
+
+// apodDone reports whether the APOD for date and its image are mirrored for
+// good, and how many files that is.
+func (m *Mirror) apodDone(date time.Time) (int, bool) {
+	img := &nasa.APODImage{Date: nasa.Date{Time: date}}
+
+	rel := apodPath(img, ".json")
+	if !m.has(rel) {
+		return 0, false
+	}
+	if !m.cfg.APOD.Images {
+		return 1, true
+	}
+
+	if err := m.readJSON(rel, img); err != nil {
+		return 0, false
+	}
+
+	u := m.apodImageURL(img)
+	if u == "" {
+		return 1, true
+	}
+	return 2, m.has(apodPath(img, urlExt(u, ".jpg")))
+}
@@ -0,0 +1,180 @@
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/Oshuma/nasa"
+)
+
+// DefaultRefreshDays is the default Config.RefreshDays.
+const DefaultRefreshDays = 7
+
+// Config selects what to mirror. It is usually loaded from a JSON file:
+//
+//	{
+//	  "dir": "/srv/nasa",
+//	  "apod": {"start": "2020-01-01", "images": true},
+//	  "epic": {"start": "2020-04-01", "end": "2020-04-30", "collections": ["natural", "enhanced"], "images": true},
+//	  "mars": {"rovers": ["curiosity"], "cameras": ["fhaz", "navcam"], "start_sol": 1000, "end_sol": 1010, "images": true}
+//	}
+type Config struct {
+	// Dir is the root directory of the mirror.
+	Dir string `json:"dir"`
+
+	// RefreshDays is how many days records stay subject to change. Records
+	// for more recent dates are fetched again on every run. It defaults to
+	// DefaultRefreshDays.
+	RefreshDays int `json:"refresh_days,omitempty"`
+
+	APOD *APODConfig `json:"apod,omitempty"`
+	EPIC *EPICConfig `json:"epic,omitempty"`
+	Mars *MarsConfig `json:"mars,omitempty"`
+}
+
+// APODConfig selects the Astronomy Pictures Of the Day to mirror.
+type APODConfig struct {
+	// Start defaults to nasa.APODFirstDate and End to today.
+	Start nasa.Date `json:"start"`
+	End   nasa.Date `json:"end"`
+
+	// Images downloads the image of each APOD; videos are skipped.
+	Images bool `json:"images,omitempty"`
+
+	// SD downloads the standard rather than the HD image.
+	SD bool `json:"sd,omitempty"`
+}
+
+// EPICConfig selects the EPIC days to mirror.
+type EPICConfig struct {
+	// Start is required; End defaults to today.
+	Start nasa.Date `json:"start"`
+	End   nasa.Date `json:"end"`
+
+	// Collections are the EPIC collections to mirror. It defaults to the
+	// natural collection.
+	Collections []nasa.EPICCollection `json:"collections,omitempty"`
+
+	// Images downloads the PNG of each image.
+	Images bool `json:"images,omitempty"`
+
+	// Thumbs also downloads the JPEG thumbnail of each downloaded image.
+	Thumbs bool `json:"thumbs,omitempty"`
+}
+
+// MarsConfig selects the rover photos to mirror.
+type MarsConfig struct {
+	// Rovers are rover slugs, such as "curiosity".
+	Rovers []string `json:"rovers"`
+
+	// Cameras are camera slugs, such as "fhaz". Empty means every camera.
+	Cameras []string `json:"cameras,omitempty"`
+
+	// StartSol and EndSol are the range of sols to mirror, inclusive.
+	// EndSol defaults to StartSol.
+	StartSol int `json:"start_sol"`
+	EndSol   int `json:"end_sol,omitempty"`
+
+	// Images downloads the image of each photo.
+	Images bool `json:"images,omitempty"`
+
+	rovers  []nasa.Rover
+	cameras []nasa.RoverCamera
+}
+
+// LoadConfig reads a JSON Config from path.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// validate checks the config and fills in defaults.
+func (cfg *Config) validate() error {
+	if cfg.Dir == "" {
+		return fmt.Errorf("mirror: no directory configured")
+	}
+
+	if cfg.RefreshDays <= 0 {
+		cfg.RefreshDays = DefaultRefreshDays
+	}
+
+	if cfg.EPIC != nil {
+		e := *cfg.EPIC
+		cfg.EPIC = &e
+
+		if e.Start.IsZero() {
+			return fmt.Errorf("mirror: no EPIC start date configured")
+		}
+
+		if len(e.Collections) == 0 {
+			e.Collections = []nasa.EPICCollection{nasa.EPICCollectionNatural}
+		}
+		for _, c := range e.Collections {
+			if !c.Valid() {
+				return fmt.Errorf("mirror: unknown EPIC collection %q", c)
+			}
+		}
+	}
+
+	if cfg.Mars != nil {
+		// Defaults are filled in on a copy, leaving the caller's config alone.
+		m := *cfg.Mars
+		cfg.Mars = &m
+
+		if m.EndSol == 0 {
+			m.EndSol = m.StartSol
+		}
+		if m.EndSol < m.StartSol {
+			return fmt.Errorf("mirror: Mars end sol %d precedes start sol %d", m.EndSol, m.StartSol)
+		}
+
+		m.rovers = nil
+		for _, slug := range m.Rovers {
+			r, ok := findRover(slug)
+			if !ok {
+				return fmt.Errorf("mirror: unknown rover %q", slug)
+			}
+			m.rovers = append(m.rovers, r)
+		}
+
+		m.cameras = nil
+		for _, slug := range m.Cameras {
+			c, ok := findCamera(slug)
+			if !ok {
+				return fmt.Errorf("mirror: unknown camera %q", slug)
+			}
+			m.cameras = append(m.cameras, c)
+		}
+	}
+
+	return nil
+}
+
+func findRover(slug string) (nasa.Rover, bool) {
+	for _, r := range nasa.Rovers {
+		if strings.EqualFold(r.Slug, slug) {
+			return r, true
+		}
+	}
+	return nasa.Rover{}, false
+}
+
+func findCamera(slug string) (nasa.RoverCamera, bool) {
+	for _, c := range nasa.RoverCameras {
+		if strings.EqualFold(c.Slug, slug) {
+			return c, true
+		}
+	}
+	return nasa.RoverCamera{}, false
+}
@@ -0,0 +1,104 @@
+package mirror
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+func epicDir(collection nasa.EPICCollection, date time.Time) string {
+	return "epic/" + string(collection) + "/" + date.Format("2006/01/02")
+}
+
+func (m *Mirror) syncEPIC(ctx context.Context) error {
+	cfg := m.cfg.EPIC
+
+	end := cfg.End.Time
+	if end.IsZero() {
+		end = time.Now().UTC()
+	}
+
+	for _, collection := range cfg.Collections {
+		for day := cfg.Start.Time; !day.After(end); day = day.AddDate(0, 0, 1) {
+			images, err := m.epicDay(ctx, collection, day)
+			if err != nil {
+				return err
+			}
+
+			if !cfg.Images {
+				continue
+			}
+
+			for _, img := range images {
+				if err := m.epicImages(ctx, collection, img); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// epicDay returns the images of day in collection, fetching them unless
+// they are mirrored for good.
+func (m *Mirror) epicDay(ctx context.Context, collection nasa.EPICCollection, day time.Time) (nasa.EPICImages, error) {
+	rel := epicDir(collection, day) + "/images.json"
+
+	images := nasa.EPICImages{}
+	if m.has(rel) {
+		if err := m.readJSON(rel, &images); err == nil {
+			m.stats.Skipped++
+			return images, nil
+		}
+	}
+
+	images, err := m.c.EPICContext(ctx, &nasa.EPICParams{Date: day, Collection: collection})
+	if err != nil {
+		return nil, err
+	}
+
+	return images, m.writeJSON(rel, m.isFinal(day), images)
+}
+
+// epicImages downloads the PNG and, if configured, the thumbnail of img
+// into their place in the EPIC archive layout.
+func (m *Mirror) epicImages(ctx context.Context, collection nasa.EPICCollection, img *nasa.EPICImage) error {
+	// Images mirrored before their collection was recorded have none.
+	if img.Collection == "" {
+		c := *img
+		c.Collection = collection
+		img = &c
+	}
+
+	type variant struct {
+		v      nasa.EPICImageVariant
+		source string
+	}
+
+	// Images read back from the mirror have no URLs, leaving the source empty.
+	variants := []variant{{nasa.EPICImagePNG, img.URL.Image}}
+	if m.cfg.EPIC.Thumbs {
+		variants = append(variants, variant{nasa.EPICImageThumb, img.URL.Thumb.Image})
+	}
+
+	for _, v := range variants {
+		v := v
+		rel, err := img.ArchivePath(v.v)
+		if err != nil {
+			return err
+		}
+
+		err = m.download("epic/"+rel, v.source, func(w io.Writer) error {
+			_, err := m.c.DownloadEPICImageContext(ctx, img, v.v, w)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
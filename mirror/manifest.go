@@ -0,0 +1,116 @@
+package mirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ManifestFile is the name of the manifest in the mirror directory.
+const ManifestFile = "manifest.json"
+
+// Manifest records every file in the mirror.
+type Manifest struct {
+	Updated time.Time        `json:"updated"`
+	Files   map[string]*File `json:"files"`
+}
+
+// File is a mirrored file.
+type File struct {
+	// Source is the URL an image was downloaded from, with any API key redacted.
+	Source string `json:"source,omitempty"`
+
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+
+	// Final reports whether the file can no longer change upstream.
+	Final bool `json:"final"`
+
+	Fetched time.Time `json:"fetched"`
+}
+
+func loadManifest(path string) (*Manifest, error) {
+	m := &Manifest{Files: make(map[string]*File)}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]*File)
+	}
+
+	return m, nil
+}
+
+func (m *Manifest) save(path string) error {
+	m.Updated = time.Now().UTC()
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(append(b, '\n'))
+		return err
+	})
+}
+
+// writeFileAtomic writes path through a temporary file in the same
+// directory, so path is either the old or the complete new file.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+
+	err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// hashFile returns the size and SHA-256 checksum of the file at path.
+func hashFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
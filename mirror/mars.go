@@ -0,0 +1,92 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Oshuma/nasa"
+)
+
+func marsDir(rover nasa.Rover, sol int) string {
+	return "mars/" + rover.Slug + "/" + strconv.Itoa(sol)
+}
+
+func (m *Mirror) syncMars(ctx context.Context) error {
+	cfg := m.cfg.Mars
+
+	for _, rover := range cfg.rovers {
+		for sol := cfg.StartSol; sol <= cfg.EndSol; sol++ {
+			photos, err := m.marsSol(ctx, rover, sol)
+			if err != nil {
+				return err
+			}
+
+			if !cfg.Images {
+				continue
+			}
+
+			for _, p := range photos {
+				p := p
+				rel := fmt.Sprintf("%s/%s/%d%s", marsDir(rover, sol), strings.ToLower(p.Camera.Name), p.ID, urlExt(p.Image, ".jpg"))
+				err := m.download(rel, p.Image, func(w io.Writer) error {
+					_, err := m.c.DownloadRoverPhotoContext(ctx, p, w)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// marsSol returns the photos of the configured cameras on sol, fetching them
+// unless they are mirrored for good.
+func (m *Mirror) marsSol(ctx context.Context, rover nasa.Rover, sol int) ([]*nasa.RoverPhoto, error) {
+	rel := marsDir(rover, sol) + "/photos.json"
+
+	photos := []*nasa.RoverPhoto{}
+	if m.has(rel) {
+		if err := m.readJSON(rel, &photos); err == nil {
+			m.stats.Skipped++
+			return photos, nil
+		}
+	}
+
+	cameras := m.cfg.Mars.cameras
+	if len(cameras) == 0 {
+		cameras = []nasa.RoverCamera{{}}
+	}
+
+	photos = []*nasa.RoverPhoto{}
+	for _, camera := range cameras {
+		if camera.Slug != "" && !rover.HasCamera(camera) {
+			continue
+		}
+
+		it := m.c.MarsRoverPhotosIteratorContext(ctx, &nasa.MarsPhotosIteratorParams{Sol: sol, Camera: camera}, rover)
+		for it.Next() {
+			photos = append(photos, it.Photo())
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(photos, func(i, j int) bool { return photos[i].ID < photos[j].ID })
+
+	// A sol is final once it has photos taken long enough ago; an empty
+	// sol may still receive them.
+	final := len(photos) > 0
+	for _, p := range photos {
+		final = final && m.isFinal(p.EarthDate.Time)
+	}
+
+	return photos, m.writeJSON(rel, final, photos)
+}
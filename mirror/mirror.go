@@ -0,0 +1,258 @@
+// Package mirror maintains a local copy of APOD, EPIC and Mars rover photo
+// data: the API records as JSON and, optionally, their images.
+//
+// Files are laid out by date under the mirror directory:
+//
+//	apod/2020/04/2020-04-29.json
+//	apod/2020/04/2020-04-29.jpg
+//	epic/natural/2020/04/24/images.json
+//	epic/natural/2020/04/24/png/epic_1b_20200424002712.png
+//	epic/enhanced/2020/04/24/thumbs/epic_RGB_20200424002712.jpg
+//	mars/curiosity/1000/photos.json
+//	mars/curiosity/1000/fhaz/102693.jpg
+//	manifest.json
+//
+// The manifest records the size and checksum of every file. Later runs skip
+// files which are final and intact, fetch records which may still change
+// again, and only rewrite files whose content changed.
+package mirror
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+// Mirror syncs a local directory with the APIs selected by a Config.
+type Mirror struct {
+	c        *nasa.Client
+	cfg      Config
+	manifest *Manifest
+	stats    Stats
+}
+
+// Stats counts the files handled by a Sync.
+type Stats struct {
+	// Written is the number of new or changed files.
+	Written int
+
+	// Unchanged is the number of records fetched again but unchanged.
+	Unchanged int
+
+	// Skipped is the number of files already mirrored and not fetched.
+	Skipped int
+}
+
+// New returns a Mirror using c to fetch the data selected by cfg. The
+// manifest is loaded from the mirror directory if it exists.
+func New(c *nasa.Client, cfg *Config) (*Mirror, error) {
+	m := &Mirror{c: c, cfg: *cfg}
+	if err := m.cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	manifest, err := loadManifest(m.path(ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m.manifest = manifest
+
+	return m, nil
+}
+
+// Manifest returns the manifest of the mirror. It must not be modified.
+func (m *Mirror) Manifest() *Manifest {
+	return m.manifest
+}
+
+// Sync brings the mirror up to date and saves the manifest, even if an
+// error stopped it early, so the next run continues where it left off.
+func (m *Mirror) Sync(ctx context.Context) (Stats, error) {
+	m.stats = Stats{}
+
+	err := m.sync(ctx)
+	if serr := m.manifest.save(m.path(ManifestFile)); err == nil {
+		err = serr
+	}
+
+	return m.stats, err
+}
+
+func (m *Mirror) sync(ctx context.Context) error {
+	if m.cfg.APOD != nil {
+		if err := m.syncAPOD(ctx); err != nil {
+			return err
+		}
+	}
+	if m.cfg.EPIC != nil {
+		if err := m.syncEPIC(ctx); err != nil {
+			return err
+		}
+	}
+	if m.cfg.Mars != nil {
+		if err := m.syncMars(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify checks every file in the manifest against its checksum and returns
+// the missing or corrupt ones, in order. They are dropped from the manifest
+// so the next Sync fetches them again.
+func (m *Mirror) Verify() ([]string, error) {
+	bad := []string{}
+	for rel, f := range m.manifest.Files {
+		size, sum, err := hashFile(m.path(rel))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if err != nil || size != f.Size || sum != f.SHA256 {
+			bad = append(bad, rel)
+		}
+	}
+	sort.Strings(bad)
+
+	if len(bad) == 0 {
+		return bad, nil
+	}
+
+	for _, rel := range bad {
+		delete(m.manifest.Files, rel)
+	}
+	return bad, m.manifest.save(m.path(ManifestFile))
+}
+
+// path returns the absolute path of the slash separated rel.
+func (m *Mirror) path(rel string) string {
+	return filepath.Join(m.cfg.Dir, filepath.FromSlash(rel))
+}
+
+// isFinal reports whether records for date can no longer change upstream.
+func (m *Mirror) isFinal(date time.Time) bool {
+	return date.Before(time.Now().UTC().AddDate(0, 0, -m.cfg.RefreshDays))
+}
+
+// has reports whether rel is mirrored for good: final, present and the
+// recorded size.
+func (m *Mirror) has(rel string) bool {
+	f, ok := m.manifest.Files[rel]
+	if !ok || !f.Final {
+		return false
+	}
+
+	fi, err := os.Stat(m.path(rel))
+	return err == nil && fi.Size() == f.Size
+}
+
+// readJSON decodes the mirrored file rel into v.
+func (m *Mirror) readJSON(rel string, v interface{}) error {
+	b, err := ioutil.ReadFile(m.path(rel))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// writeJSON stores v as rel, leaving the file alone if it is unchanged.
+func (m *Mirror) writeJSON(rel string, final bool, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	sum := sha256.Sum256(b)
+	f := &File{
+		Size:    int64(len(b)),
+		SHA256:  hex.EncodeToString(sum[:]),
+		Final:   final,
+		Fetched: time.Now().UTC(),
+	}
+
+	if old, ok := m.manifest.Files[rel]; ok && old.SHA256 == f.SHA256 {
+		if fi, err := os.Stat(m.path(rel)); err == nil && fi.Size() == f.Size {
+			m.manifest.Files[rel] = f
+			m.stats.Unchanged++
+			return nil
+		}
+	}
+
+	err = writeFileAtomic(m.path(rel), func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	m.manifest.Files[rel] = f
+	m.stats.Written++
+	return nil
+}
+
+// download stores the image written by fetch as rel, unless it is already
+// mirrored. Images never change once published.
+func (m *Mirror) download(rel, source string, fetch func(io.Writer) error) error {
+	if m.has(rel) {
+		m.stats.Skipped++
+		return nil
+	}
+
+	h := sha256.New()
+	var size int64
+	err := writeFileAtomic(m.path(rel), func(w io.Writer) error {
+		cw := &countWriter{w: io.MultiWriter(w, h)}
+		err := fetch(cw)
+		size = cw.n
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	m.manifest.Files[rel] = &File{
+		Source:  nasa.RedactURL(source),
+		Size:    size,
+		SHA256:  hex.EncodeToString(h.Sum(nil)),
+		Final:   true,
+		Fetched: time.Now().UTC(),
+	}
+	m.stats.Written++
+	return nil
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// urlExt returns the lower case file extension of the URL u, or def.
+func urlExt(u, def string) string {
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+
+	ext := strings.ToLower(path.Ext(u))
+	if ext == "" || len(ext) > 5 {
+		return def
+	}
+	return ext
+}
@@ -0,0 +1,148 @@
+package mirror
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Oshuma/nasa"
+	"github.com/Oshuma/nasa/nasatest"
+)
+
+func TestMirror(t *testing.T) {
+	s := nasatest.NewServer()
+	defer s.Close()
+
+	dir, err := ioutil.TempDir("", "nasa-mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfgPath := filepath.Join(dir, "mirror.json")
+	err = ioutil.WriteFile(cfgPath, []byte(`{
+		"dir": "`+filepath.ToSlash(filepath.Join(dir, "data"))+`",
+		"apod": {"start": "2020-04-27", "end": "2020-04-29", "images": true},
+		"epic": {"start": "2020-04-24", "end": "2020-04-24", "collections": ["natural", "enhanced"], "images": true, "thumbs": true},
+		"mars": {"rovers": ["curiosity"], "cameras": ["fhaz", "navcam"], "start_sol": 1000, "end_sol": 1001, "images": true}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := s.NewClient(nasa.WithAPIKey("NASA_KEY"))
+	m, err := New(c, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("first sync", func(t *testing.T) {
+		stats, err := m.Sync(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// APOD: 3 records, 2 images (one is a video).
+		// EPIC: per collection 1 listing, 3 images and 3 thumbnails.
+		// Mars: 2 sols and 20 FHAZ and NAVCAM photos.
+		if stats.Written != 41 {
+			t.Errorf("expected 41 files written, got: %+v", stats)
+		}
+
+		for _, rel := range []string{
+			"apod/2020/04/2020-04-29.json",
+			"apod/2020/04/2020-04-29.jpg",
+			"epic/natural/2020/04/24/images.json",
+			"epic/natural/2020/04/24/png/epic_1b_20200424002712.png",
+			"epic/natural/2020/04/24/thumbs/epic_1b_20200424002712.jpg",
+			"epic/enhanced/2020/04/24/png/epic_RGB_20200424002712.png",
+			"mars/curiosity/1000/photos.json",
+			"mars/curiosity/1000/fhaz/102693.jpg",
+			ManifestFile,
+		} {
+			if _, err := os.Stat(filepath.Join(cfg.Dir, rel)); err != nil {
+				t.Errorf("expected %s: %v", rel, err)
+			}
+		}
+	})
+
+	t.Run("incremental", func(t *testing.T) {
+		m, err := New(c, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		requests := s.Requests()
+		stats, err := m.Sync(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stats.Written != 0 || stats.Skipped != 41 {
+			t.Errorf("expected 41 files skipped, got: %+v", stats)
+		}
+
+		if s.Requests() != requests {
+			t.Errorf("expected no requests, got: %d", s.Requests()-requests)
+		}
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		rel := "mars/curiosity/1000/fhaz/102693.jpg"
+		if err := ioutil.WriteFile(filepath.Join(cfg.Dir, rel), []byte("corrupt"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		bad, err := m.Verify()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(bad) != 1 || bad[0] != rel {
+			t.Errorf("expected: [%s], got: %v", rel, bad)
+		}
+
+		stats, err := m.Sync(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stats.Written != 1 {
+			t.Errorf("expected 1 file written, got: %+v", stats)
+		}
+
+		if bad, _ := m.Verify(); len(bad) != 0 {
+			t.Errorf("expected no bad files, got: %v", bad)
+		}
+	})
+}
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"no dir", Config{}},
+		{"no EPIC start", Config{Dir: "data", EPIC: &EPICConfig{}}},
+		{"unknown EPIC collection", Config{Dir: "data", EPIC: &EPICConfig{Start: nasa.Date{Time: time.Now()}, Collections: []nasa.EPICCollection{"infrared"}}}},
+		{"unknown rover", Config{Dir: "data", Mars: &MarsConfig{Rovers: []string{"sojourner"}}}},
+		{"unknown camera", Config{Dir: "data", Mars: &MarsConfig{Cameras: []string{"hubble"}}}},
+		{"sol range", Config{Dir: "data", Mars: &MarsConfig{StartSol: 10, EndSol: 5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(nasa.NewClient(), &tt.cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -1,10 +1,5 @@
 package nasa
 
-import (
-	"io/ioutil"
-	"net/http"
-)
-
 // Version is the package version.
 const Version = "0.1.2"
 
@@ -28,31 +23,3 @@ type XYZ struct {
 	Y float64 `json:"y"`
 	Z float64 `json:"z"`
 }
-
-func getContent(url string, p ParamEncoder) ([]byte, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if p != nil {
-		query, err := p.Encode()
-		if err != nil {
-			return nil, err
-		}
-		req.URL.RawQuery = query
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	content, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	return content, nil
-}
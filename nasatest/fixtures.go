@@ -0,0 +1,182 @@
+package nasatest
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"sync"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+// Fixture identifiers seeded by NewServer.
+const (
+	FixtureAPODDate   = "2020-04-29"
+	FixtureEPICDate   = "2020-04-24"
+	FixtureEPICImage  = "epic_1b_20200424002712"
+	FixtureMarsSol    = 1000
+	FixtureMarsPhotos = 30
+	FixtureNasaID     = "as11-40-5874"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func (s *Server) seed() {
+	s.AddAPOD(nasa.APODImage{
+		Date:           nasa.Date{Time: day("2020-04-27")},
+		Title:          "Fixture Nebula",
+		URL:            s.URL + ImagesPath + "/apod/fixture_nebula1024.jpg",
+		HDURL:          s.URL + ImagesPath + "/apod/fixture_nebula.jpg",
+		Explanation:    "A fixture image of an emission nebula used for testing.",
+		MediaType:      "image",
+		Copyright:      "Fixture Observatory",
+		ServiceVersion: "v1",
+	})
+	s.AddAPOD(nasa.APODImage{
+		Date:           nasa.Date{Time: day("2020-04-28")},
+		Title:          "Fixture Launch Video",
+		URL:            "https://www.youtube.com/embed/dQw4w9WgXcQ?rel=0",
+		ThumbnailURL:   "https://img.youtube.com/vi/dQw4w9WgXcQ/0.jpg",
+		Explanation:    "A fixture video of a rocket launch used for testing.",
+		MediaType:      "video",
+		ServiceVersion: "v1",
+	})
+	s.AddAPOD(nasa.APODImage{
+		Date:           nasa.Date{Time: day(FixtureAPODDate)},
+		Title:          "Fixture Galaxy",
+		URL:            s.URL + ImagesPath + "/apod/fixture_galaxy1024.jpg",
+		HDURL:          s.URL + ImagesPath + "/apod/fixture_galaxy.jpg",
+		Explanation:    "A fixture image of a spiral galaxy used for testing.",
+		MediaType:      "image",
+		ServiceVersion: "v1",
+	})
+
+	for i, ts := range []string{"002712", "021515", "040318"} {
+		img := &nasa.EPICImage{
+			Identifier: "20200424" + ts[:4],
+			Caption:    "This image was taken by NASA's EPIC camera onboard the NOAA DSCOVR spacecraft",
+			Image:      "epic_1b_20200424" + ts,
+			Version:    "03",
+		}
+		t, _ := time.Parse("2006-01-02 150405", FixtureEPICDate+" "+ts)
+		img.Date = nasa.EPICDate{Time: t}
+		img.Coords.Centroid = nasa.LatLon{Lat: 7.3, Lon: 171.5 - float64(i)*27.2}
+		img.Coords.Dscovr = nasa.XYZ{X: 1245892.5, Y: 742077.25, Z: 185788.22}
+		img.Coords.Lunar = nasa.XYZ{X: 252279.91, Y: 251550.58, Z: 88125.78}
+		img.Coords.Sun = nasa.XYZ{X: 124295768, Y: 77789147, Z: 33720946}
+		img.Coords.Attitude = nasa.Quaternions{Q0: -0.31526, Q1: 0.20133, Q2: 0.13245, Q3: 0.91734}
+		s.AddEPIC(img)
+
+		enhanced := *img
+		enhanced.Image = "epic_RGB_20200424" + ts
+		enhanced.Collection = nasa.EPICCollectionEnhanced
+		s.AddEPIC(&enhanced)
+	}
+
+	curiosity := &nasa.RoverPhoto{}
+	curiosity.Rover.ID = 5
+	curiosity.Rover.Name = "Curiosity"
+	curiosity.Rover.LandingDate = nasa.Date{Time: day("2012-08-06")}
+	curiosity.Rover.LaunchDate = nasa.Date{Time: day("2011-11-26")}
+	curiosity.Rover.Status = "active"
+
+	cameras := []nasa.RoverCamera{nasa.RoverCameraFHAZ, nasa.RoverCameraNAVCAM, nasa.RoverCameraMAST}
+	for i := 0; i < FixtureMarsPhotos; i++ {
+		sol := FixtureMarsSol
+		if i >= FixtureMarsPhotos-2 {
+			sol = FixtureMarsSol + 1
+		}
+		cam := cameras[i%len(cameras)]
+
+		p := &nasa.RoverPhoto{
+			ID:        102693 + i,
+			Sol:       sol,
+			Image:     fmt.Sprintf("%s%s/mars/%05d/%s_%03d.JPG", s.URL, ImagesPath, sol, cam.Name, i),
+			EarthDate: nasa.Date{Time: day("2015-05-30").AddDate(0, 0, sol-FixtureMarsSol)},
+		}
+		p.Camera.ID = 20 + i%len(cameras)
+		p.Camera.Name = cam.Name
+		p.Camera.FullName = cam.FullName
+		p.Camera.RoverID = 5
+		p.Rover = curiosity.Rover
+		s.AddRoverPhotos(nasa.RoverCuriosity.Slug, p)
+	}
+
+	s.SetManifest(nasa.RoverCuriosity.Slug, nasa.MissionManifest{
+		Name:        "Curiosity",
+		LandingDate: nasa.Date{Time: day("2012-08-06")},
+		LaunchDate:  nasa.Date{Time: day("2011-11-26")},
+		Status:      "active",
+		MaxSol:      FixtureMarsSol + 1,
+		MaxDate:     nasa.Date{Time: day("2015-05-31")},
+		TotalPhotos: FixtureMarsPhotos,
+		Photos: []nasa.ManifestPhoto{
+			{Sol: FixtureMarsSol, EarthDate: nasa.Date{Time: day("2015-05-30")}, TotalPhotos: FixtureMarsPhotos - 2, Cameras: []string{"FHAZ", "MAST", "NAVCAM"}},
+			{Sol: FixtureMarsSol + 1, EarthDate: nasa.Date{Time: day("2015-05-31")}, TotalPhotos: 2, Cameras: []string{"FHAZ", "NAVCAM"}},
+		},
+	})
+
+	s.AddMedia(&MediaItem{
+		NasaID:      FixtureNasaID,
+		Title:       "Apollo 11 Mission image - Astronaut on the lunar surface",
+		Description: "A fixture image of an astronaut on the lunar surface used for testing.",
+		MediaType:   "image",
+		Center:      "JSC",
+		Keywords:    []string{"APOLLO 11", "Moon"},
+		DateCreated: time.Date(1969, 7, 20, 0, 0, 0, 0, time.UTC),
+		Assets:      []string{FixtureNasaID + "~orig.jpg", FixtureNasaID + "~thumb.jpg"},
+		Captions:    "1\n00:00:00,000 --> 00:00:02,000\nFixture caption.\n",
+		Metadata: nasa.MediaMetadata{
+			AVAILCenter:    "JSC",
+			AVAILNASAID:    FixtureNasaID,
+			AVAILTitle:     "Apollo 11 Mission image - Astronaut on the lunar surface",
+			AVAILMediaType: "image",
+			AVAILKeywords:  []string{"APOLLO 11", "Moon"},
+			FileMIMEType:   "image/jpeg",
+		},
+	})
+}
+
+var (
+	placeholderOnce sync.Once
+	placeholderP    []byte
+	placeholderJ    []byte
+)
+
+// placeholderImages encodes a small solid image served for every image request.
+func placeholderImages() {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			img.Set(x, y, color.RGBA{R: 30, G: 80, B: 200, A: 255})
+		}
+	}
+
+	var b bytes.Buffer
+	png.Encode(&b, img)
+	placeholderP = b.Bytes()
+
+	b = bytes.Buffer{}
+	jpeg.Encode(&b, img, nil)
+	placeholderJ = b.Bytes()
+}
+
+func placeholderPNG() []byte {
+	placeholderOnce.Do(placeholderImages)
+	return placeholderP
+}
+
+func placeholderJPEG() []byte {
+	placeholderOnce.Do(placeholderImages)
+	return placeholderJ
+}
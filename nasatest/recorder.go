@@ -0,0 +1,214 @@
+package nasatest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Oshuma/nasa"
+)
+
+// Mode is the mode a Recorder runs in.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette without touching the network.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests to the real APIs and records the responses.
+	ModeRecord
+)
+
+// Interaction is a recorded HTTP request and its response.
+type Interaction struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+type cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper which records API interactions to a
+// cassette file, or replays them from one. API keys are redacted from
+// everything it records.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	played       map[string]int
+}
+
+// NewRecorder returns a Recorder for the cassette at path. In ModeReplay the
+// cassette must exist; in ModeRecord it is written by Save.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		played:    make(map[string]int),
+	}
+
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := cassette{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	r.interactions = c.Interactions
+
+	return r, nil
+}
+
+// SetTransport sets the transport used to reach the real APIs in ModeRecord.
+func (r *Recorder) SetTransport(t http.RoundTripper) {
+	r.transport = t
+}
+
+// Option configures a nasa.Client to send all requests through the recorder.
+func (r *Recorder) Option() nasa.Option {
+	return nasa.WithHTTPClient(&http.Client{Transport: r})
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	i := &Interaction{
+		Method:     req.Method,
+		URL:        nasa.RedactURL(req.URL.String()),
+		StatusCode: resp.StatusCode,
+		Header:     header,
+	}
+	if utf8.Valid(body) {
+		i.Body = nasa.RedactURL(string(body))
+	} else {
+		i.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, i)
+	r.mu.Unlock()
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + nasa.RedactURL(req.URL.String())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Repeated requests are answered in recorded order, then with the last match.
+	var match *Interaction
+	seen := 0
+	for _, i := range r.interactions {
+		if i.Method+" "+i.URL != key {
+			continue
+		}
+		match = i
+		if seen == r.played[key] {
+			break
+		}
+		seen++
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("nasatest: no recorded interaction for %s in %s", key, r.path)
+	}
+	r.played[key]++
+
+	body := []byte(match.Body)
+	if match.BodyBase64 != "" {
+		b, err := base64.StdEncoding.DecodeString(match.BodyBase64)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	header := match.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", match.StatusCode, http.StatusText(match.StatusCode)),
+		StatusCode:    match.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file. It does
+// nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	b, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(b, '\n'), 0644)
+}
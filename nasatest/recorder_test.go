@@ -0,0 +1,86 @@
+package nasatest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nasatest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewServer()
+	defer s.Close()
+
+	path := filepath.Join(dir, "cassette.json")
+	d, _ := time.Parse("2006-01-02", FixtureEPICDate)
+
+	rec, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.SetTransport(s.Client().Transport)
+
+	c := nasa.NewClient(s.Option(), rec.Option(), nasa.WithAPIKey("SECRET_KEY"))
+	recorded, err := c.EPIC(&nasa.EPICParams{Date: d})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "SECRET_KEY") {
+		t.Error("API key not redacted from cassette")
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		before := s.Requests()
+
+		rec, err := NewRecorder(path, ModeReplay)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c := nasa.NewClient(s.Option(), rec.Option(), nasa.WithAPIKey("OTHER_KEY"))
+		replayed, err := c.EPIC(&nasa.EPICParams{Date: d})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(replayed) != len(recorded) || replayed[0].Image != recorded[0].Image {
+			t.Errorf("expected: %v, got: %v", recorded, replayed)
+		}
+
+		if s.Requests() != before {
+			t.Error("replay should not reach the server")
+		}
+	})
+
+	t.Run("missing interaction", func(t *testing.T) {
+		rec, err := NewRecorder(path, ModeReplay)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c := nasa.NewClient(s.Option(), rec.Option(), nasa.WithAPIKey("OTHER_KEY"))
+		if _, err := c.APOD(&nasa.APODParams{}); err == nil {
+			t.Error("expected an error for an unrecorded request")
+		}
+	})
+}
@@ -0,0 +1,777 @@
+// Package nasatest provides an in-process fake of the NASA APIs for testing
+// code built on the nasa package without hitting the network.
+package nasatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+// Base paths of each API on the fake server.
+const (
+	APODPath   = "/planetary/apod"
+	EPICPath   = "/EPIC"
+	MarsPath   = "/mars-photos/api/v1"
+	MediaPath  = "/images-api"
+	AssetsPath = "/images-assets"
+	ImagesPath = "/images"
+)
+
+// marsPageSize is the number of photos returned per page by the Mars photos API.
+const marsPageSize = 25
+
+// MediaItem is an item in the fake NASA Image and Video Library.
+type MediaItem struct {
+	NasaID      string
+	Title       string
+	Description string
+	MediaType   string
+	Center      string
+	Keywords    []string
+	DateCreated time.Time
+	Assets      []string
+	Captions    string
+	Metadata    nasa.MediaMetadata
+}
+
+// Server is a fake NASA API server seeded with fixture data.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	apod      map[string]nasa.APODImage
+	epic      map[nasa.EPICCollection]map[string]nasa.EPICImages
+	photos    map[string][]*nasa.RoverPhoto
+	manifests map[string]nasa.MissionManifest
+	media     map[string]*MediaItem
+
+	errors    []injectedError
+	latency   time.Duration
+	limit     int
+	remaining map[string]int
+	requests  int
+}
+
+type injectedError struct {
+	prefix string
+	status int
+	body   string
+	n      int
+}
+
+// NewServer starts a fake server seeded with the fixture data. The image
+// URLs of seeded APODs and rover photos point at the server too. The
+// caller must call Close when done.
+func NewServer() *Server {
+	s := NewUnseededServer()
+	s.seed()
+	return s
+}
+
+// NewUnseededServer starts a fake server with no data. The caller must call
+// Close when done.
+func NewUnseededServer() *Server {
+	s := &Server{
+		apod:      make(map[string]nasa.APODImage),
+		epic:      make(map[nasa.EPICCollection]map[string]nasa.EPICImages),
+		photos:    make(map[string][]*nasa.RoverPhoto),
+		manifests: make(map[string]nasa.MissionManifest),
+		media:     make(map[string]*MediaItem),
+		remaining: make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Option configures a nasa.Client to send all requests to the server.
+func (s *Server) Option() nasa.Option {
+	opts := []nasa.Option{
+		nasa.WithHTTPClient(s.Client()),
+		nasa.WithAPODURL(s.URL + APODPath),
+		nasa.WithEPICURL(s.URL + EPICPath),
+		nasa.WithMarsURL(s.URL + MarsPath),
+		nasa.WithMediaURL(s.URL + MediaPath),
+	}
+
+	return func(c *nasa.Client) {
+		for _, opt := range opts {
+			opt(c)
+		}
+	}
+}
+
+// NewClient returns a nasa.Client wired to the server.
+func (s *Server) NewClient(opts ...nasa.Option) *nasa.Client {
+	return nasa.NewClient(append([]nasa.Option{s.Option()}, opts...)...)
+}
+
+// AddAPOD adds an APOD entry, replacing any on the same date.
+func (s *Server) AddAPOD(img nasa.APODImage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apod[img.Date.Format("2006-01-02")] = img
+}
+
+// AddEPIC adds EPIC images to their collection, or the natural collection
+// if they have none, grouped by their date.
+func (s *Server) AddEPIC(images ...*nasa.EPICImage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, img := range images {
+		collection := img.Collection
+		if collection == "" {
+			collection = nasa.EPICCollectionNatural
+		}
+		if s.epic[collection] == nil {
+			s.epic[collection] = make(map[string]nasa.EPICImages)
+		}
+
+		day := img.Date.Format("2006-01-02")
+		s.epic[collection][day] = append(s.epic[collection][day], img)
+	}
+}
+
+// AddRoverPhotos adds photos taken by the rover with the given slug.
+func (s *Server) AddRoverPhotos(rover string, photos ...*nasa.RoverPhoto) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.photos[rover] = append(s.photos[rover], photos...)
+}
+
+// SetManifest sets the mission manifest for the rover with the given slug.
+func (s *Server) SetManifest(rover string, m nasa.MissionManifest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.manifests[rover] = m
+}
+
+// AddMedia adds an item to the image and video library.
+func (s *Server) AddMedia(item *MediaItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.media[item.NasaID] = item
+}
+
+// InjectError makes the next n requests whose path starts with prefix respond
+// with status and body. A negative n fails every matching request.
+func (s *Server) InjectError(prefix string, status int, body string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, injectedError{prefix: prefix, status: status, body: body, n: n})
+}
+
+// ClearErrors removes all injected errors.
+func (s *Server) ClearErrors() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = nil
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetRateLimit enables X-RateLimit headers on api.nasa.gov routes, allowing
+// limit requests per API key before responding with 429 OVER_RATE_LIMIT.
+// A limit of zero disables rate limiting.
+func (s *Server) SetRateLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+	s.remaining = make(map[string]int)
+}
+
+// Requests returns the number of requests the server has received.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	latency := s.latency
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if s.injectError(w, r) {
+		return
+	}
+
+	path := r.URL.Path
+	switch {
+	case strings.HasPrefix(path, AssetsPath+"/"):
+		s.serveAssets(w, r)
+	case strings.HasPrefix(path, MediaPath+"/"):
+		s.serveMedia(w, r)
+	case strings.HasPrefix(path, EPICPath+"/archive/"):
+		s.serveEPICArchive(w, r)
+	case strings.HasPrefix(path, ImagesPath+"/"):
+		serveImage(w, r)
+	case path == APODPath, strings.HasPrefix(path, EPICPath+"/api/"), strings.HasPrefix(path, MarsPath+"/"):
+		if !s.checkAPIKey(w, r) {
+			return
+		}
+		switch {
+		case path == APODPath:
+			s.serveAPOD(w, r)
+		case strings.HasPrefix(path, EPICPath+"/api/"):
+			s.serveEPIC(w, r)
+		default:
+			s.serveMars(w, r)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) injectError(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.errors {
+		e := &s.errors[i]
+		if e.n == 0 || !strings.HasPrefix(r.URL.Path, e.prefix) {
+			continue
+		}
+		if e.n > 0 {
+			e.n--
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(e.status)
+		w.Write([]byte(e.body))
+		return true
+	}
+
+	return false
+}
+
+// checkAPIKey mimics the api.nasa.gov gateway key check and rate limiting.
+func (s *Server) checkAPIKey(w http.ResponseWriter, r *http.Request) bool {
+	key := r.URL.Query().Get("api_key")
+	if key == "" {
+		writeJSON(w, http.StatusForbidden, gatewayError("API_KEY_MISSING",
+			"No api_key was supplied. Get one at https://api.nasa.gov:443"))
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.limit == 0 {
+		return true
+	}
+
+	remaining, ok := s.remaining[key]
+	if !ok {
+		remaining = s.limit
+	}
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.limit))
+	if remaining > 0 {
+		s.remaining[key] = remaining - 1
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining-1))
+		return true
+	}
+	w.Header().Set("X-RateLimit-Remaining", "0")
+
+	w.Header().Set("Retry-After", "3600")
+	writeJSON(w, http.StatusTooManyRequests, gatewayError("OVER_RATE_LIMIT",
+		"You have exceeded your rate limit. Try again later."))
+	return false
+}
+
+func gatewayError(code, message string) interface{} {
+	return map[string]interface{}{
+		"error": map[string]string{"code": code, "message": message},
+	}
+}
+
+func (s *Server) serveAPOD(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	thumbs := q.Get("thumbs") == "true"
+
+	if start := q.Get("start_date"); start != "" {
+		end := q.Get("end_date")
+		if end == "" {
+			end = s.latestAPOD()
+		}
+
+		images := []nasa.APODImage{}
+		for _, d := range s.apodDates() {
+			if d >= start && d <= end {
+				images = append(images, apodResponse(s.apod[d], thumbs))
+			}
+		}
+		writeJSON(w, http.StatusOK, images)
+		return
+	}
+
+	// Count returns the first entries rather than random ones, so tests are repeatable.
+	if count, _ := strconv.Atoi(q.Get("count")); count > 0 {
+		images := []nasa.APODImage{}
+		for _, d := range s.apodDates() {
+			if len(images) == count {
+				break
+			}
+			images = append(images, apodResponse(s.apod[d], thumbs))
+		}
+		writeJSON(w, http.StatusOK, images)
+		return
+	}
+
+	date := q.Get("date")
+	if date == "" {
+		date = s.latestAPOD()
+	}
+
+	img, ok := s.apod[date]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"code":            404,
+			"msg":             "No data available for date: " + date,
+			"service_version": "v1",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, apodResponse(img, thumbs))
+}
+
+// apodResponse drops the thumbnail unless it was asked for, like the real API.
+func apodResponse(img nasa.APODImage, thumbs bool) nasa.APODImage {
+	if !thumbs {
+		img.ThumbnailURL = ""
+	}
+	return img
+}
+
+// apodDates returns the dates of every APOD entry in order.
+func (s *Server) apodDates() []string {
+	dates := make([]string, 0, len(s.apod))
+	for d := range s.apod {
+		dates = append(dates, d)
+	}
+	sort.Strings(dates)
+	return dates
+}
+
+func (s *Server) latestAPOD() string {
+	latest := ""
+	for d := range s.apod {
+		if d > latest {
+			latest = d
+		}
+	}
+	return latest
+}
+
+func (s *Server) serveEPIC(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, EPICPath+"/api/"), "/")
+	collection := nasa.EPICCollection(parts[0])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days, ok := s.epic[collection]
+	if !ok && !collection.Valid() {
+		http.NotFound(w, r)
+		return
+	}
+
+	var day string
+	switch {
+	case len(parts) == 1:
+		for d := range days {
+			if d > day {
+				day = d
+			}
+		}
+	case len(parts) == 3 && parts[1] == "date":
+		day = parts[2]
+	case len(parts) == 2 && parts[1] == "available":
+		writeJSON(w, http.StatusOK, epicDays(days))
+		return
+	case len(parts) == 2 && parts[1] == "all":
+		all := []map[string]string{}
+		for _, d := range epicDays(days) {
+			all = append(all, map[string]string{"date": d})
+		}
+		writeJSON(w, http.StatusOK, all)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	images := days[day]
+	if images == nil {
+		images = nasa.EPICImages{}
+	}
+	writeJSON(w, http.StatusOK, images)
+}
+
+// epicDays returns the days with images, newest first like the EPIC API.
+func epicDays(days map[string]nasa.EPICImages) []string {
+	list := []string{}
+	for d, images := range days {
+		if len(images) > 0 {
+			list = append(list, d)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(list)))
+	return list
+}
+
+// serveEPICArchive serves a placeholder image for any archive path of a known image.
+func (s *Server) serveEPICArchive(w http.ResponseWriter, r *http.Request) {
+	// /EPIC/archive/{collection}/{yyyy}/{mm}/{dd}/{png|jpg|thumbs}/{name}.{ext}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, EPICPath+"/archive/"), "/")
+	if len(parts) != 6 {
+		http.NotFound(w, r)
+		return
+	}
+
+	day := strings.Join(parts[1:4], "-")
+	file := parts[5]
+	dot := strings.LastIndex(file, ".")
+	if dot < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	name, ext := file[:dot], file[dot+1:]
+
+	s.mu.Lock()
+	found := false
+	for _, img := range s.epic[nasa.EPICCollection(parts[0])][day] {
+		if img.Image == name {
+			found = true
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch ext {
+	case "png":
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(placeholderPNG())
+	case "jpg":
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(placeholderJPEG())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveMars(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, MarsPath+"/"), "/")
+
+	switch {
+	case len(parts) == 3 && parts[0] == "rovers" && parts[2] == "photos":
+		s.serveMarsPhotos(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "rovers" && parts[2] == "latest_photos":
+		s.serveMarsLatestPhotos(w, r, parts[1])
+	case len(parts) == 2 && parts[0] == "manifests":
+		s.serveMarsManifest(w, r, parts[1])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveMarsPhotos(w http.ResponseWriter, r *http.Request, rover string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.knownRover(rover) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"errors": "Invalid Rover Name"})
+		return
+	}
+
+	q := r.URL.Query()
+	earthDate := q.Get("earth_date")
+	sol, _ := strconv.Atoi(q.Get("sol"))
+	camera := strings.ToLower(q.Get("camera"))
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	matched := []*nasa.RoverPhoto{}
+	for _, p := range s.photos[rover] {
+		if earthDate != "" && p.EarthDate.Format("2006-01-02") != earthDate {
+			continue
+		}
+		if earthDate == "" && p.Sol != sol {
+			continue
+		}
+		if camera != "" && strings.ToLower(p.Camera.Name) != camera {
+			continue
+		}
+		matched = append(matched, p)
+	}
+
+	photos := []*nasa.RoverPhoto{}
+	start := (page - 1) * marsPageSize
+	if start < len(matched) {
+		end := start + marsPageSize
+		if end > len(matched) {
+			end = len(matched)
+		}
+		photos = matched[start:end]
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"photos": photos})
+}
+
+func (s *Server) serveMarsLatestPhotos(w http.ResponseWriter, r *http.Request, rover string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.knownRover(rover) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"errors": "Invalid Rover Name"})
+		return
+	}
+
+	maxSol := -1
+	for _, p := range s.photos[rover] {
+		if p.Sol > maxSol {
+			maxSol = p.Sol
+		}
+	}
+
+	photos := []*nasa.RoverPhoto{}
+	for _, p := range s.photos[rover] {
+		if p.Sol == maxSol {
+			photos = append(photos, p)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"latest_photos": photos})
+}
+
+func (s *Server) serveMarsManifest(w http.ResponseWriter, r *http.Request, rover string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.manifests[rover]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"errors": "Invalid Rover Name"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"photo_manifest": m})
+}
+
+func (s *Server) knownRover(rover string) bool {
+	_, hasPhotos := s.photos[rover]
+	_, hasManifest := s.manifests[rover]
+	return hasPhotos || hasManifest
+}
+
+func (s *Server) serveMedia(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, MediaPath+"/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "search":
+		s.serveMediaSearch(w, r)
+	case len(parts) == 2 && parts[0] == "asset":
+		s.serveMediaAsset(w, r, parts[1])
+	case len(parts) == 2 && parts[0] == "metadata":
+		s.serveMediaLocation(w, r, parts[1], "metadata.json")
+	case len(parts) == 2 && parts[0] == "captions":
+		s.serveMediaLocation(w, r, parts[1], "captions.srt")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveMediaSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := strings.ToLower(q.Get("q"))
+	if query == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"reason": "Expected 'q' text search parameter or other keywords.",
+		})
+		return
+	}
+	mediaType := q.Get("media_type")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.media))
+	for id := range s.media {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	items := []interface{}{}
+	for _, id := range ids {
+		m := s.media[id]
+		text := strings.ToLower(m.Title + " " + m.Description + " " + strings.Join(m.Keywords, " "))
+		if !strings.Contains(text, query) {
+			continue
+		}
+		if mediaType != "" && m.MediaType != mediaType {
+			continue
+		}
+
+		items = append(items, map[string]interface{}{
+			"href": fmt.Sprintf("%s%s/%s/collection.json", s.URL, AssetsPath, m.NasaID),
+			"data": []interface{}{map[string]interface{}{
+				"center":       m.Center,
+				"title":        m.Title,
+				"keywords":     m.Keywords,
+				"description":  m.Description,
+				"media_type":   m.MediaType,
+				"nasa_id":      m.NasaID,
+				"date_created": m.DateCreated.Format(time.RFC3339),
+			}},
+			"links": []interface{}{map[string]string{
+				"render": "image",
+				"rel":    "preview",
+				"href":   fmt.Sprintf("%s%s/%s/%s~thumb.jpg", s.URL, AssetsPath, m.NasaID, m.NasaID),
+			}},
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"version":  "1.0",
+			"href":     s.URL + r.URL.String(),
+			"items":    items,
+			"metadata": map[string]int{"total_hits": len(items)},
+			"links":    []interface{}{},
+		},
+	})
+}
+
+func (s *Server) serveMediaAsset(w http.ResponseWriter, r *http.Request, id string) {
+	s.mu.Lock()
+	m, ok := s.media[id]
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"reason": "The requested resource was not found."})
+		return
+	}
+
+	items := []interface{}{}
+	for _, a := range m.Assets {
+		items = append(items, map[string]string{
+			"href": fmt.Sprintf("%s%s/%s/%s", s.URL, AssetsPath, m.NasaID, a),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"collection": map[string]interface{}{
+			"version": "1.0",
+			"href":    s.URL + r.URL.String(),
+			"items":   items,
+		},
+	})
+}
+
+// serveMediaLocation serves the images-api two-hop responses, pointing at a file in the assets.
+func (s *Server) serveMediaLocation(w http.ResponseWriter, r *http.Request, id, file string) {
+	s.mu.Lock()
+	m, ok := s.media[id]
+	s.mu.Unlock()
+
+	if !ok || file == "captions.srt" && m.Captions == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"reason": "The requested resource was not found."})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"location": fmt.Sprintf("%s%s/%s/%s", s.URL, AssetsPath, id, file),
+	})
+}
+
+// serveImage serves a placeholder for any image file, standing in for the
+// hosts of APOD and rover photo images.
+func serveImage(w http.ResponseWriter, r *http.Request) {
+	switch strings.ToLower(filepath.Ext(r.URL.Path)) {
+	case ".png":
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(placeholderPNG())
+	case ".jpg", ".jpeg":
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(placeholderJPEG())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) serveAssets(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, AssetsPath+"/"), "/")
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	m, ok := s.media[parts[0]]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch file := parts[1]; {
+	case file == "metadata.json":
+		writeJSON(w, http.StatusOK, m.Metadata)
+	case file == "captions.srt" && m.Captions != "":
+		w.Header().Set("Content-Type", "application/x-subrip")
+		w.Write([]byte(m.Captions))
+	case strings.HasSuffix(file, ".jpg"):
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(placeholderJPEG())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
@@ -0,0 +1,250 @@
+package nasatest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+func TestServer(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := s.NewClient(nasa.WithAPIKey("NASA_KEY"))
+
+	t.Run("APOD", func(t *testing.T) {
+		img, err := c.APOD(&nasa.APODParams{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if img.Date.Format("2006-01-02") != FixtureAPODDate {
+			t.Errorf("expected: %s, got: %s", FixtureAPODDate, img.Date.Format("2006-01-02"))
+		}
+	})
+
+	t.Run("APOD range", func(t *testing.T) {
+		start, _ := time.Parse("2006-01-02", "2020-04-27")
+		images, err := c.APODList(&nasa.APODParams{StartDate: start})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(images) != 3 {
+			t.Errorf("expected 3 images, got: %d", len(images))
+		}
+	})
+
+	t.Run("APOD video", func(t *testing.T) {
+		d, _ := time.Parse("2006-01-02", "2020-04-28")
+		img, err := c.APOD(&nasa.APODParams{Date: d})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !img.IsVideo() || img.ThumbnailURL != "" {
+			t.Errorf("expected a video without thumbnail, got: %+v", img)
+		}
+
+		img, err = c.APOD(&nasa.APODParams{Date: d, Thumbs: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if img.ThumbnailURL == "" {
+			t.Error("expected a thumbnail URL")
+		}
+	})
+
+	t.Run("APOD not found", func(t *testing.T) {
+		_, err := c.APOD(&nasa.APODParams{Date: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)})
+		if !errors.Is(err, nasa.ErrorNotFound) {
+			t.Errorf("expected ErrorNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("EPIC", func(t *testing.T) {
+		d, _ := time.Parse("2006-01-02", FixtureEPICDate)
+		images, err := c.EPIC(&nasa.EPICParams{Date: d})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(images) != 3 || images[0].Image != FixtureEPICImage {
+			t.Fatalf("unexpected images: %v", images)
+		}
+
+		resp, err := s.Client().Get(images[0].URL.Thumb.Enhanced)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/jpeg" {
+			t.Errorf("unexpected archive response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+	})
+
+	t.Run("EPIC dates", func(t *testing.T) {
+		dates, err := c.EPICAvailableDates(&nasa.EPICParams{Collection: nasa.EPICCollectionEnhanced})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(dates) != 1 || dates[0].Format("2006-01-02") != FixtureEPICDate {
+			t.Errorf("unexpected dates: %v", dates)
+		}
+	})
+
+	t.Run("Mars photos", func(t *testing.T) {
+		p := &nasa.MarsPhotosParams{Sol: FixtureMarsSol}
+		photos, err := c.MarsRoverPhotos(p, nasa.RoverCuriosity)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(photos.Photos) != 25 {
+			t.Errorf("expected 25 photos, got: %d", len(photos.Photos))
+		}
+
+		p.Page = 2
+		photos, err = c.MarsRoverPhotos(p, nasa.RoverCuriosity)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(photos.Photos) != FixtureMarsPhotos-2-25 {
+			t.Errorf("expected %d photos, got: %d", FixtureMarsPhotos-2-25, len(photos.Photos))
+		}
+	})
+
+	t.Run("Mars latest and manifest", func(t *testing.T) {
+		latest, err := c.MarsRoverPhotosLatest(&nasa.APIParam{}, nasa.RoverCuriosity)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := c.MarsMissionManifest(&nasa.APIParam{}, nasa.RoverCuriosity)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(latest) != 2 || latest[0].Sol != m.MaxSol {
+			t.Errorf("expected 2 photos on sol %d, got: %d", m.MaxSol, len(latest))
+		}
+
+		_, err = c.MarsMissionManifest(&nasa.APIParam{}, nasa.RoverSpirit)
+		if !errors.Is(err, nasa.ErrorNotFound) {
+			t.Errorf("expected ErrorNotFound, got: %v", err)
+		}
+	})
+
+	t.Run("media", func(t *testing.T) {
+		media, err := c.MediaSearch(&nasa.MediaParams{Query: "apollo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if media.Metadata.TotalHits != 1 || media.Items[0].Data[0].NasaID != FixtureNasaID {
+			t.Fatalf("unexpected search result: %+v", media)
+		}
+
+		assets, err := c.GetMediaAssets(FixtureNasaID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(assets.Items) != 2 {
+			t.Errorf("expected 2 assets, got: %d", len(assets.Items))
+		}
+
+		metadata, err := c.GetMediaMetadata(FixtureNasaID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if metadata.AVAILNASAID != FixtureNasaID {
+			t.Errorf("expected: %s, got: %s", FixtureNasaID, metadata.AVAILNASAID)
+		}
+
+		captions, err := c.GetMediaCaptions(FixtureNasaID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(captions, "Fixture caption.") {
+			t.Errorf("unexpected captions: %s", captions)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		before := s.Requests()
+		_, err := s.NewClient().APOD(&nasa.APODParams{})
+		if err != nasa.ErrorNoAPIKey {
+			t.Errorf("wrong error returned: %v", err)
+		}
+
+		if s.Requests() != before {
+			t.Error("request should not have been sent")
+		}
+	})
+}
+
+func TestServerFaults(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := s.NewClient(nasa.WithAPIKey("NASA_KEY"))
+
+	t.Run("injected error", func(t *testing.T) {
+		s.InjectError(APODPath, http.StatusServiceUnavailable, "", 1)
+
+		_, err := c.APOD(&nasa.APODParams{})
+		apiErr := &nasa.APIError{}
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected 503, got: %v", err)
+		}
+
+		if _, err := c.APOD(&nasa.APODParams{}); err != nil {
+			t.Errorf("expected error to be injected once, got: %v", err)
+		}
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		s.SetRateLimit(2)
+		defer s.SetRateLimit(0)
+
+		for i := 0; i < 2; i++ {
+			if _, err := c.APOD(&nasa.APODParams{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if r := c.RateLimit(); r.Limit != 2 || r.Remaining != 0 {
+			t.Errorf("expected 0/2, got: %d/%d", r.Remaining, r.Limit)
+		}
+
+		_, err := c.APOD(&nasa.APODParams{})
+		if !errors.Is(err, nasa.ErrorRateLimited) {
+			t.Errorf("expected ErrorRateLimited, got: %v", err)
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		s.SetLatency(time.Second)
+		defer s.SetLatency(0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := c.APODContext(ctx, &nasa.APODParams{})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+		}
+	})
+}
@@ -12,6 +12,12 @@ type ParamEncoder interface {
 	Encode() (string, error)
 }
 
+// apiKeyer is implemented by params which carry an API key.
+type apiKeyer interface {
+	apiKey() string
+	withAPIKey(key string) ParamEncoder
+}
+
 // APIParam is used when only an APIKey is needed.
 type APIParam struct {
 	APIKey string
@@ -29,11 +35,29 @@ func (p *APIParam) Encode() (string, error) {
 	return v.Encode(), nil
 }
 
+func (p *APIParam) apiKey() string { return p.APIKey }
+
+func (p *APIParam) withAPIKey(key string) ParamEncoder {
+	c := *p
+	c.APIKey = key
+	return &c
+}
+
 // APODParams wraps the APOD API params.
+//
+// Date, StartDate/EndDate and Count are mutually exclusive. StartDate (with
+// an optional EndDate, defaulting to today) and Count return several entries
+// and are used with APODList.
 type APODParams struct {
-	APIKey string
-	Date   time.Time
-	HD     bool
+	APIKey    string
+	Date      time.Time
+	StartDate time.Time
+	EndDate   time.Time
+	Count     int
+	Thumbs    bool
+
+	// Deprecated: HD is ignored by the API, which always returns HDURL.
+	HD bool
 }
 
 // Encode returns a string representation for the given API type.
@@ -45,21 +69,104 @@ func (p *APODParams) Encode() (string, error) {
 	}
 	v.Set("api_key", p.APIKey)
 
+	if err := p.validate(); err != nil {
+		return "", err
+	}
+
 	if !p.Date.IsZero() {
 		v.Set("date", p.Date.Format("2006-01-02"))
 	}
 
+	if !p.StartDate.IsZero() {
+		v.Set("start_date", p.StartDate.Format("2006-01-02"))
+	}
+
+	if !p.EndDate.IsZero() {
+		v.Set("end_date", p.EndDate.Format("2006-01-02"))
+	}
+
+	if p.Count > 0 {
+		v.Set("count", strconv.Itoa(p.Count))
+	}
+
 	if p.HD {
 		v.Set("hd", "true")
 	}
 
+	if p.Thumbs {
+		v.Set("thumbs", "true")
+	}
+
 	return v.Encode(), nil
 }
 
+// APODFirstDate is the date of the first Astronomy Picture Of the Day.
+var APODFirstDate = time.Date(1995, 6, 16, 0, 0, 0, 0, time.UTC)
+
+// apodMaxCount is the largest count the APOD API accepts.
+const apodMaxCount = 100
+
+// apodToday returns today's date, at midnight UTC, in the time zone furthest
+// ahead, so no caller's "today" is rejected. The API itself rejects dates
+// after today in US Eastern time.
+func apodToday() time.Time {
+	y, m, d := time.Now().In(time.FixedZone("UTC+14", 14*60*60)).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// apodInRange reports whether the calendar date of t has an APOD.
+func apodInRange(t time.Time) bool {
+	y, m, d := t.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return !day.Before(APODFirstDate) && !day.After(apodToday())
+}
+
+func (p *APODParams) validate() error {
+	if p.multiple() && !p.Date.IsZero() || !p.StartDate.IsZero() && p.Count > 0 {
+		return ErrorAPODParamsConflict
+	}
+
+	if !p.EndDate.IsZero() && p.StartDate.IsZero() {
+		return ErrorAPODInvalidRange
+	}
+
+	if p.Count < 0 || p.Count > apodMaxCount {
+		return ErrorAPODInvalidCount
+	}
+
+	for _, d := range []time.Time{p.Date, p.StartDate, p.EndDate} {
+		if !d.IsZero() && !apodInRange(d) {
+			return ErrorAPODDateOutOfRange
+		}
+	}
+
+	if !p.EndDate.IsZero() && p.EndDate.Before(p.StartDate) {
+		return ErrorAPODInvalidRange
+	}
+
+	return nil
+}
+
+// multiple reports whether the params request more than one APOD.
+func (p *APODParams) multiple() bool {
+	return !p.StartDate.IsZero() || !p.EndDate.IsZero() || p.Count > 0
+}
+
+func (p *APODParams) apiKey() string { return p.APIKey }
+
+func (p *APODParams) withAPIKey(key string) ParamEncoder {
+	c := *p
+	c.APIKey = key
+	return &c
+}
+
 // EPICParams wraps the EPIC API params.
 type EPICParams struct {
 	APIKey string
 	Date   time.Time
+
+	// Collection defaults to EPICCollectionNatural.
+	Collection EPICCollection
 }
 
 // Encode returns a string representation for the given API type.
@@ -68,7 +175,12 @@ func (p *EPICParams) Encode() (string, error) {
 		return "", ErrorNoAPIKey
 	}
 
-	val := "api/natural"
+	collection := p.collection()
+	if !collection.Valid() {
+		return "", ErrorUnknownEPICCollection
+	}
+
+	val := "api/" + string(collection)
 
 	if !p.Date.IsZero() {
 		val += fmt.Sprintf("/date/%s", p.Date.Format("2006-01-02"))
@@ -79,6 +191,21 @@ func (p *EPICParams) Encode() (string, error) {
 	return val, nil
 }
 
+func (p *EPICParams) collection() EPICCollection {
+	if p.Collection == "" {
+		return EPICCollectionNatural
+	}
+	return p.Collection
+}
+
+func (p *EPICParams) apiKey() string { return p.APIKey }
+
+func (p *EPICParams) withAPIKey(key string) ParamEncoder {
+	c := *p
+	c.APIKey = key
+	return &c
+}
+
 // MarsPhotosParams wraps the Mars Photos API params.
 type MarsPhotosParams struct {
 	APIKey    string
@@ -114,6 +241,14 @@ func (p *MarsPhotosParams) Encode() (string, error) {
 	return v.Encode(), nil
 }
 
+func (p *MarsPhotosParams) apiKey() string { return p.APIKey }
+
+func (p *MarsPhotosParams) withAPIKey(key string) ParamEncoder {
+	c := *p
+	c.APIKey = key
+	return &c
+}
+
 // MediaParams wraps the Image and Video Library (media) params.
 type MediaParams struct {
 	Query            string
@@ -98,6 +98,63 @@ func TestEncode(t *testing.T) {
 		})
 	})
 
+	t.Run("APODParams range", func(t *testing.T) {
+		start := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2020, 4, 7, 0, 0, 0, 0, time.UTC)
+
+		t.Run("start and end", func(t *testing.T) {
+			p := &APODParams{APIKey: apiKey, StartDate: start, EndDate: end, Thumbs: true}
+
+			out, err := p.Encode()
+			if err != nil {
+				t.Error(err)
+			}
+
+			expected := fmt.Sprintf("api_key=%s&end_date=2020-04-07&start_date=2020-04-01&thumbs=true", apiKey)
+			if out != expected {
+				t.Errorf("expected: %s, got: %s", expected, out)
+			}
+		})
+
+		t.Run("count", func(t *testing.T) {
+			p := &APODParams{APIKey: apiKey, Count: 5}
+
+			out, err := p.Encode()
+			if err != nil {
+				t.Error(err)
+			}
+
+			expected := fmt.Sprintf("api_key=%s&count=5", apiKey)
+			if out != expected {
+				t.Errorf("expected: %s, got: %s", expected, out)
+			}
+		})
+
+		invalid := []struct {
+			name string
+			p    *APODParams
+			err  error
+		}{
+			{"date and start", &APODParams{APIKey: apiKey, Date: start, StartDate: start}, ErrorAPODParamsConflict},
+			{"date and count", &APODParams{APIKey: apiKey, Date: start, Count: 5}, ErrorAPODParamsConflict},
+			{"start and count", &APODParams{APIKey: apiKey, StartDate: start, Count: 5}, ErrorAPODParamsConflict},
+			{"end only", &APODParams{APIKey: apiKey, EndDate: end}, ErrorAPODInvalidRange},
+			{"end before start", &APODParams{APIKey: apiKey, StartDate: end, EndDate: start}, ErrorAPODInvalidRange},
+			{"before first", &APODParams{APIKey: apiKey, Date: time.Date(1995, 6, 15, 0, 0, 0, 0, time.UTC)}, ErrorAPODDateOutOfRange},
+			{"future", &APODParams{APIKey: apiKey, StartDate: time.Now().AddDate(0, 0, 3)}, ErrorAPODDateOutOfRange},
+			{"count too large", &APODParams{APIKey: apiKey, Count: 101}, ErrorAPODInvalidCount},
+		}
+
+		for _, tt := range invalid {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.p.Encode()
+				if err != tt.err {
+					t.Errorf("wrong error returned: %v", err)
+				}
+			})
+		}
+	})
+
 	t.Run("EPICParams", func(t *testing.T) {
 		t.Run("no APIKey", func(t *testing.T) {
 			p := EPICParams{}
@@ -139,6 +196,29 @@ func TestEncode(t *testing.T) {
 				t.Errorf("expected: %s, got: %s", expected, out)
 			}
 		})
+
+		t.Run("collection", func(t *testing.T) {
+			p := &EPICParams{APIKey: apiKey, Collection: EPICCollectionAerosol}
+
+			out, err := p.Encode()
+			if err != nil {
+				t.Error(err)
+			}
+
+			expected := fmt.Sprintf("api/aerosol?api_key=%s", apiKey)
+			if out != expected {
+				t.Errorf("expected: %s, got: %s", expected, out)
+			}
+		})
+
+		t.Run("unknown collection", func(t *testing.T) {
+			p := &EPICParams{APIKey: apiKey, Collection: "infrared"}
+
+			_, err := p.Encode()
+			if err != ErrorUnknownEPICCollection {
+				t.Errorf("wrong error returned: %v", err)
+			}
+		})
 	})
 
 	t.Run("MarsPhotosParams", func(t *testing.T) {
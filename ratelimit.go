@@ -0,0 +1,184 @@
+package nasa
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// rateLimitWindow is the rolling window api.nasa.gov applies its hourly limit over.
+const rateLimitWindow = time.Hour
+
+// RateLimit is the request quota reported by api.nasa.gov for an API key.
+type RateLimit struct {
+	// Limit is the number of requests allowed per hour.
+	Limit int
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Updated is when the quota was last reported.
+	Updated time.Time
+}
+
+// Exhausted reports whether the quota is used up and the window has not yet rolled over.
+func (r RateLimit) Exhausted() bool {
+	return !r.Updated.IsZero() && r.Remaining <= 0 && time.Since(r.Updated) < rateLimitWindow
+}
+
+// Reset returns the approximate time at which the quota is restored.
+func (r RateLimit) Reset() time.Time {
+	return r.Updated.Add(rateLimitWindow)
+}
+
+// RateLimitPolicy decides what happens before a request whose API key has no quota left.
+type RateLimitPolicy int
+
+const (
+	// RateLimitIgnore sends the request anyway.
+	RateLimitIgnore RateLimitPolicy = iota
+
+	// RateLimitFailFast returns a *RateLimitError without sending the request.
+	RateLimitFailFast
+
+	// RateLimitWait blocks until the quota is restored or the context is done.
+	RateLimitWait
+)
+
+// WithRateLimitPolicy sets what the client does when a request would exceed the quota.
+func WithRateLimitPolicy(p RateLimitPolicy) Option {
+	return func(c *Client) {
+		c.rateLimitPolicy = p
+	}
+}
+
+// RateLimitError is returned when the API responds with 429 Too Many Requests,
+// or when the client refuses to send a request because of RateLimitFailFast.
+type RateLimitError struct {
+	// RateLimit is the last quota seen for the API key.
+	RateLimit RateLimit
+
+	// RetryAfter is a hint of how long to wait before trying again.
+	RetryAfter time.Duration
+
+	// Err is the underlying API error; nil if the request was never sent.
+	Err *APIError
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s (retry after %s)", e.Err, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s: %d/%d requests remaining (retry after %s)",
+		ErrorRateLimited, e.RateLimit.Remaining, e.RateLimit.Limit, e.RetryAfter)
+}
+
+// Is reports whether target is ErrorRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrorRateLimited
+}
+
+// Unwrap returns the underlying *APIError.
+func (e *RateLimitError) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// RateLimit returns the most recently reported quota, for any API key.
+func (c *Client) RateLimit() RateLimit {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastRateLimit
+}
+
+// RateLimitFor returns the most recently reported quota for the given API key.
+func (c *Client) RateLimitFor(key string) (RateLimit, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.rateLimits[key]
+	return r, ok
+}
+
+// checkRateLimit applies the client RateLimitPolicy before a request made with key.
+func (c *Client) checkRateLimit(ctx context.Context, key string) error {
+	if c.rateLimitPolicy == RateLimitIgnore {
+		return nil
+	}
+
+	r, ok := c.RateLimitFor(key)
+	if !ok || !r.Exhausted() {
+		return nil
+	}
+
+	wait := time.Until(r.Reset())
+	if c.rateLimitPolicy == RateLimitFailFast {
+		return &RateLimitError{RateLimit: r, RetryAfter: wait}
+	}
+
+	return sleep(ctx, wait)
+}
+
+// recordRateLimit stores the quota reported in h for the given API key.
+func (c *Client) recordRateLimit(key string, h http.Header) {
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	r := RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Updated:   time.Now(),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rateLimits[key] = r
+	c.lastRateLimit = r
+}
+
+// newRateLimitError wraps a 429 APIError with the quota for key and a retry hint.
+// The key is marked as exhausted even if the response had no quota headers.
+func (c *Client) newRateLimitError(key string, apiErr *APIError, h http.Header) *RateLimitError {
+	c.mu.Lock()
+	r := c.rateLimits[key]
+	if r.Remaining > 0 || r.Updated.IsZero() {
+		r.Remaining = 0
+		r.Updated = time.Now()
+		c.rateLimits[key] = r
+		c.lastRateLimit = r
+	}
+	c.mu.Unlock()
+
+	return &RateLimitError{
+		RateLimit:  r,
+		RetryAfter: retryAfter(h, r.Reset()),
+		Err:        apiErr,
+	}
+}
+
+// retryAfter parses the Retry-After header, falling back to the time until reset.
+func retryAfter(h http.Header, reset time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return time.Until(t)
+		}
+	}
+
+	if d := time.Until(reset); d > 0 {
+		return d
+	}
+	return 0
+}
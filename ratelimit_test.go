@@ -0,0 +1,94 @@
+package nasa
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	remaining := "10"
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "30")
+		w.Header().Set("X-RateLimit-Remaining", remaining)
+		if remaining == "0" {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"code":"OVER_RATE_LIMIT","message":"You have exceeded your rate limit."}}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithAPODURL(ts.URL), WithRateLimitPolicy(RateLimitFailFast))
+	p := &APODParams{APIKey: "NASA_KEY"}
+
+	t.Run("recorded", func(t *testing.T) {
+		_, err := c.APOD(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, ok := c.RateLimitFor("NASA_KEY")
+		if !ok {
+			t.Fatal("rate limit not recorded")
+		}
+
+		if r.Limit != 30 || r.Remaining != 10 {
+			t.Errorf("expected: 10/30, got: %d/%d", r.Remaining, r.Limit)
+		}
+
+		if c.RateLimit() != r {
+			t.Errorf("expected: %v, got: %v", r, c.RateLimit())
+		}
+	})
+
+	t.Run("429", func(t *testing.T) {
+		remaining = "0"
+		_, err := c.APOD(p)
+
+		rlErr := &RateLimitError{}
+		if !errors.As(err, &rlErr) {
+			t.Fatalf("expected *RateLimitError, got: %v", err)
+		}
+
+		if rlErr.RetryAfter != 120*time.Second {
+			t.Errorf("expected: 2m0s, got: %s", rlErr.RetryAfter)
+		}
+
+		if !errors.Is(err, ErrorRateLimited) {
+			t.Error("expected error to match ErrorRateLimited")
+		}
+
+		apiErr := &APIError{}
+		if !errors.As(err, &apiErr) || apiErr.Code != "OVER_RATE_LIMIT" {
+			t.Errorf("expected wrapped APIError, got: %v", err)
+		}
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		before := requests
+		_, err := c.APOD(p)
+
+		if !errors.Is(err, ErrorRateLimited) {
+			t.Errorf("expected ErrorRateLimited, got: %v", err)
+		}
+
+		if requests != before {
+			t.Error("request should not have been sent")
+		}
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		remaining = "10"
+		_, err := c.APOD(&APODParams{APIKey: "OTHER_KEY"})
+		if err != nil {
+			t.Error(err)
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package nasa
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how failed GET requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry; it doubles with each attempt.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts, including Retry-After hints.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction (0 to 1) of each wait which is randomized.
+	Jitter float64
+
+	// RetryableStatus lists the response status codes which are retried.
+	RetryableStatus []int
+
+	// Retryable reports whether a transport error is retried.
+	// If nil, timeouts, connection resets and unexpected EOFs are retried.
+	Retryable func(err error) bool
+
+	// OnAttempt, if set, is called after every attempt.
+	OnAttempt func(RetryAttempt)
+}
+
+// RetryAttempt describes a single attempt at a request.
+type RetryAttempt struct {
+	// Attempt is the 1-based attempt number.
+	Attempt int
+
+	// URL is the request URL with the API key redacted.
+	URL string
+
+	// StatusCode is the response status code, or 0 if no response was received.
+	StatusCode int
+
+	// Err is the error from this attempt, if any.
+	Err error
+
+	// Wait is how long the client will wait before the next attempt;
+	// zero if the request will not be retried.
+	Wait time.Duration
+}
+
+// DefaultRetryPolicy retries transient gateway errors up to 3 times.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     4,
+	MinBackoff:      500 * time.Millisecond,
+	MaxBackoff:      30 * time.Second,
+	Jitter:          0.5,
+	RetryableStatus: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests.
+// Requests are not retried unless a policy is given.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = p
+	}
+}
+
+// backoff returns how long to wait after the given failed attempt.
+func (p RetryPolicy) backoff(attempt int, h http.Header) time.Duration {
+	wait := p.MinBackoff << uint(attempt-1)
+	if wait <= 0 || (p.MaxBackoff > 0 && wait > p.MaxBackoff) {
+		wait = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		wait -= time.Duration(p.Jitter * randFloat64() * float64(wait))
+	}
+
+	if h != nil && h.Get("Retry-After") != "" {
+		if ra := retryAfter(h, time.Time{}); ra > wait {
+			wait = ra
+		}
+		if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+			wait = p.MaxBackoff
+		}
+	}
+
+	return wait
+}
+
+// retryable reports whether an attempt which got resp and err may be retried.
+func (p RetryPolicy) retryable(resp *http.Response, err error) bool {
+	if resp != nil {
+		for _, code := range p.RetryableStatus {
+			if resp.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	if err == nil {
+		return false
+	}
+
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return isTransient(err)
+}
+
+// isTransient reports whether err is a transport error worth retrying.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// isIdempotent reports whether req may safely be sent more than once.
+func isIdempotent(req *http.Request) bool {
+	return req.Method == http.MethodGet || req.Method == http.MethodHead
+}
+
+var (
+	randMu sync.Mutex
+	rnd    = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func randFloat64() float64 {
+	randMu.Lock()
+	defer randMu.Unlock()
+	return rnd.Float64()
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
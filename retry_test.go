@@ -0,0 +1,127 @@
+package nasa
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:     3,
+		MinBackoff:      time.Millisecond,
+		MaxBackoff:      10 * time.Millisecond,
+		Jitter:          0.5,
+		RetryableStatus: []int{http.StatusServiceUnavailable},
+	}
+
+	t.Run("recovers", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"title":"Test APOD"}`))
+		}))
+		defer ts.Close()
+
+		var attempts []RetryAttempt
+		p := policy
+		p.OnAttempt = func(a RetryAttempt) {
+			attempts = append(attempts, a)
+		}
+
+		c := NewClient(WithAPODURL(ts.URL), WithRetryPolicy(p))
+		img, err := c.APOD(&APODParams{APIKey: "NASA_KEY"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if img.Title != "Test APOD" {
+			t.Errorf("expected: Test APOD, got: %s", img.Title)
+		}
+
+		if len(attempts) != 3 {
+			t.Fatalf("expected 3 attempts, got: %d", len(attempts))
+		}
+
+		if attempts[0].StatusCode != http.StatusServiceUnavailable || attempts[0].Wait == 0 {
+			t.Errorf("unexpected first attempt: %+v", attempts[0])
+		}
+
+		if attempts[2].StatusCode != http.StatusOK || attempts[2].Wait != 0 {
+			t.Errorf("unexpected last attempt: %+v", attempts[2])
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		c := NewClient(WithAPODURL(ts.URL), WithRetryPolicy(policy))
+		_, err := c.APOD(&APODParams{APIKey: "NASA_KEY"})
+
+		apiErr := &APIError{}
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected 503 APIError, got: %v", err)
+		}
+
+		if requests != policy.MaxAttempts {
+			t.Errorf("expected %d requests, got: %d", policy.MaxAttempts, requests)
+		}
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		c := NewClient(WithAPODURL(ts.URL), WithRetryPolicy(policy))
+		_, err := c.APOD(&APODParams{APIKey: "NASA_KEY"})
+		if !errors.Is(err, ErrorNotFound) {
+			t.Errorf("expected ErrorNotFound, got: %v", err)
+		}
+
+		if requests != 1 {
+			t.Errorf("expected 1 request, got: %d", requests)
+		}
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := RetryPolicy{
+		MinBackoff: time.Second,
+		MaxBackoff: 10 * time.Second,
+	}
+
+	tests := []struct {
+		attempt  int
+		header   http.Header
+		expected time.Duration
+	}{
+		{1, nil, time.Second},
+		{2, nil, 2 * time.Second},
+		{3, nil, 4 * time.Second},
+		{5, nil, 10 * time.Second},
+		{1, http.Header{"Retry-After": []string{"5"}}, 5 * time.Second},
+		{1, http.Header{"Retry-After": []string{"60"}}, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got := p.backoff(tt.attempt, tt.header)
+		if got != tt.expected {
+			t.Errorf("attempt %d: expected: %s, got: %s", tt.attempt, tt.expected, got)
+		}
+	}
+}
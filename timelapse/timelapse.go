@@ -0,0 +1,298 @@
+// Package timelapse builds rotating Earth animations from EPIC thumbnails.
+package timelapse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg" // thumbnails are JPEGs
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Oshuma/nasa"
+)
+
+// Defaults used for zero Options fields.
+const (
+	DefaultDelay       = 200 * time.Millisecond
+	DefaultConcurrency = 4
+)
+
+// ErrorNoImages is returned when there are no images to animate.
+var ErrorNoImages = errors.New("timelapse: no images")
+
+// Options configure an animation.
+type Options struct {
+	// Enhanced uses the enhanced rather than the natural thumbnails.
+	Enhanced bool
+
+	// Delay is how long each frame is shown. It defaults to DefaultDelay and
+	// is rounded to the 10ms steps of GIF.
+	Delay time.Duration
+
+	// MaxFrames limits the animation to that many frames, spread evenly over
+	// the images. Zero means every image.
+	MaxFrames int
+
+	// Size scales the frames to Size by Size pixels. Zero keeps the size of
+	// the thumbnails.
+	Size int
+
+	// Concurrency is how many thumbnails are downloaded at once. It defaults
+	// to DefaultConcurrency.
+	Concurrency int
+
+	// FramesDir, if set, also receives every frame as a numbered PNG:
+	// frame-0001.png, frame-0002.png and so on. It is created if needed.
+	FramesDir string
+}
+
+func (o *Options) withDefaults() Options {
+	d := Options{}
+	if o != nil {
+		d = *o
+	}
+	if d.Delay <= 0 {
+		d.Delay = DefaultDelay
+	}
+	if d.Concurrency <= 0 {
+		d.Concurrency = DefaultConcurrency
+	}
+	return d
+}
+
+// Build downloads the thumbnails of images using c and writes them to w as
+// a looping animated GIF, in the order they were taken.
+func Build(ctx context.Context, c *nasa.Client, images nasa.EPICImages, w io.Writer, opts *Options) error {
+	o := opts.withDefaults()
+
+	selected := frames(images, o.MaxFrames)
+	if len(selected) == 0 {
+		return ErrorNoImages
+	}
+
+	pics, err := download(ctx, c, selected, &o)
+	if err != nil {
+		return err
+	}
+
+	if o.FramesDir != "" {
+		if err := writeFrames(o.FramesDir, pics); err != nil {
+			return err
+		}
+	}
+
+	delay := int(o.Delay / (10 * time.Millisecond))
+	if delay < 1 {
+		delay = 1
+	}
+
+	anim := &gif.GIF{}
+	for _, pic := range pics {
+		p := image.NewPaletted(pic.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(p, p.Rect, pic, pic.Bounds().Min)
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	return gif.EncodeAll(w, anim)
+}
+
+// BuildRange is like Build with the images of every day from start to end,
+// inclusive, in the natural or enhanced collection as selected by opts.
+func BuildRange(ctx context.Context, c *nasa.Client, start, end time.Time, w io.Writer, opts *Options) error {
+	collection := nasa.EPICCollectionNatural
+	if opts != nil && opts.Enhanced {
+		collection = nasa.EPICCollectionEnhanced
+	}
+
+	images := nasa.EPICImages{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		list, err := c.EPICContext(ctx, &nasa.EPICParams{Date: day, Collection: collection})
+		if err != nil {
+			return err
+		}
+		images = append(images, list...)
+	}
+
+	return Build(ctx, c, images, w, opts)
+}
+
+// frames returns up to max of images, spread evenly and in date order.
+func frames(images nasa.EPICImages, max int) nasa.EPICImages {
+	sorted := make(nasa.EPICImages, len(images))
+	copy(sorted, images)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date.Time)
+	})
+
+	if max <= 0 || len(sorted) <= max {
+		return sorted
+	}
+
+	picked := make(nasa.EPICImages, max)
+	for i := range picked {
+		picked[i] = sorted[i*len(sorted)/max]
+	}
+	return picked
+}
+
+// download fetches and decodes the thumbnails of images concurrently,
+// returning them in the same order. The first error cancels the rest.
+func download(ctx context.Context, c *nasa.Client, images nasa.EPICImages, o *Options) ([]image.Image, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	variant := nasa.EPICNaturalThumb
+	if o.Enhanced {
+		variant = nasa.EPICEnhancedThumb
+	}
+
+	pics := make([]image.Image, len(images))
+	jobs := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+		mu.Unlock()
+	}
+
+	for n := 0; n < o.Concurrency && n < len(images); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				pic, err := thumbnail(ctx, c, images[i], variant, o.Size)
+				if err != nil {
+					fail(fmt.Errorf("%s: %w", images[i].Image, err))
+					continue
+				}
+				pics[i] = pic
+			}
+		}()
+	}
+
+feed:
+	for i := range images {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return pics, nil
+}
+
+func thumbnail(ctx context.Context, c *nasa.Client, img *nasa.EPICImage, v nasa.EPICImageVariant, size int) (image.Image, error) {
+	var b bytes.Buffer
+	if _, err := c.DownloadEPICImageContext(ctx, img, v, &b); err != nil {
+		return nil, err
+	}
+
+	pic, _, err := image.Decode(&b)
+	if err != nil {
+		return nil, err
+	}
+
+	if size > 0 {
+		pic = scale(pic, size, size)
+	}
+	return pic, nil
+}
+
+// scale resizes src to w by h pixels, averaging the source pixels covered
+// by each destination pixel.
+func scale(src image.Image, w, h int) image.Image {
+	sb := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0 := sb.Min.Y + y*sb.Dy()/h
+		y1 := sb.Min.Y + (y+1)*sb.Dy()/h
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < w; x++ {
+			x0 := sb.Min.X + x*sb.Dx()/w
+			x1 := sb.Min.X + (x+1)*sb.Dx()/w
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			// Sums of 16-bit channels overflow 32 bits past 65536 pixels.
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
+
+func writeFrames(dir string, pics []image.Image) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for i, pic := range pics {
+		path := filepath.Join(dir, fmt.Sprintf("frame-%04d.png", i+1))
+		if err := writeFrame(path, pic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFrame(path string, pic image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, pic); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
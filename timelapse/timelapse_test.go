@@ -0,0 +1,150 @@
+package timelapse
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Oshuma/nasa"
+	"github.com/Oshuma/nasa/nasatest"
+)
+
+func TestBuild(t *testing.T) {
+	s := nasatest.NewServer()
+	defer s.Close()
+	c := s.NewClient(nasa.WithAPIKey("NASA_KEY"))
+
+	day, _ := time.Parse("2006-01-02", nasatest.FixtureEPICDate)
+	images, err := c.EPIC(&nasa.EPICParams{Date: day})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "timelapse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	buf := &bytes.Buffer{}
+	opts := &Options{Enhanced: true, Delay: 250 * time.Millisecond, Size: 8, FramesDir: dir}
+	if err := Build(context.Background(), c, images, buf, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	anim, err := gif.DecodeAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(anim.Image) != 3 {
+		t.Fatalf("expected: 3 frames, got: %d", len(anim.Image))
+	}
+	for i, frame := range anim.Image {
+		if frame.Rect.Dx() != 8 || frame.Rect.Dy() != 8 {
+			t.Errorf("expected: 8x8, got: %s", frame.Rect)
+		}
+		if anim.Delay[i] != 25 {
+			t.Errorf("expected: 25, got: %d", anim.Delay[i])
+		}
+	}
+
+	for _, name := range []string{"frame-0001.png", "frame-0002.png", "frame-0003.png"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	t.Run("range", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := BuildRange(context.Background(), c, day.AddDate(0, 0, -1), day, buf, &Options{MaxFrames: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		anim, err := gif.DecodeAll(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(anim.Image) != 2 {
+			t.Errorf("expected: 2 frames, got: %d", len(anim.Image))
+		}
+		if anim.Image[0].Rect.Dx() != 16 {
+			t.Errorf("expected: 16, got: %d", anim.Image[0].Rect.Dx())
+		}
+	})
+
+	t.Run("no images", func(t *testing.T) {
+		err := Build(context.Background(), c, nasa.EPICImages{}, &bytes.Buffer{}, nil)
+		if err != ErrorNoImages {
+			t.Errorf("wrong error returned: %v", err)
+		}
+	})
+
+	t.Run("missing image", func(t *testing.T) {
+		missing := *images[0]
+		missing.Image = "epic_1b_20200424999999"
+
+		err := Build(context.Background(), c, nasa.EPICImages{images[0], &missing}, &bytes.Buffer{}, nil)
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestFrames(t *testing.T) {
+	images := nasa.EPICImages{}
+	for _, h := range []int{5, 1, 3, 0, 2, 4} {
+		img := &nasa.EPICImage{Image: string(rune('a' + h))}
+		img.Date = nasa.EPICDate{Time: time.Date(2020, 4, 24, h, 0, 0, 0, time.UTC)}
+		images = append(images, img)
+	}
+
+	tests := []struct {
+		max      int
+		expected string
+	}{
+		{0, "abcdef"},
+		{10, "abcdef"},
+		{3, "ace"},
+		{2, "ad"},
+	}
+
+	for _, tt := range tests {
+		got := ""
+		for _, img := range frames(images, tt.max) {
+			got += img.Image
+		}
+		if got != tt.expected {
+			t.Errorf("max %d: expected: %s, got: %s", tt.max, tt.expected, got)
+		}
+	}
+
+	if images[0].Image != "f" {
+		t.Error("frames reordered its argument")
+	}
+}
+
+func TestScale(t *testing.T) {
+	// Each destination pixel averages 300x300 source pixels, more than the
+	// 65536 whose 16-bit channels fit a 32-bit sum.
+	src := image.NewRGBA(image.Rect(0, 0, 600, 600))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	dst := scale(src, 2, 2)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			if c := color.RGBAModel.Convert(dst.At(x, y)); c != color.RGBAModel.Convert(color.White) {
+				t.Errorf("expected: white at %d,%d, got: %v", x, y, c)
+			}
+		}
+	}
+}